@@ -22,7 +22,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return 600, 800
+	return scene.ScreenWidth, scene.ScreenHeight
 }
 
 func Start() error {
@@ -34,7 +34,7 @@ func Start() error {
 		Level: level,
 	})))
 
-	ebiten.SetWindowSize(600, 800)
+	// The window size comes from the display settings, which the manager applies
 	ebiten.SetWindowTitle("Ebitris")
 
 	manager := scene.NewManager()
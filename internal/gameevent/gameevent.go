@@ -0,0 +1,90 @@
+// Package gameevent holds the payloads of the events games publish, and turns
+// what happens in a game into those events. The event bus itself knows
+// nothing about games.
+package gameevent
+
+import (
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/settings"
+	"github.com/piotrowski/ebitris/internal/sim"
+	"github.com/piotrowski/ebitris/internal/tetris"
+)
+
+// StartGamePayload picks the mode of a new game. Restarts leave the payload
+// out to play the last mode again.
+type StartGamePayload struct {
+	Mode sim.Mode
+}
+
+// GameOverPayload describes a game that ended, either by topping out or by
+// reaching the goal of its mode.
+type GameOverPayload struct {
+	Mode   sim.Mode
+	Score  int
+	Lines  int
+	Level  int
+	Reason tetris.GameOverReason // GameOverNone when the goal was reached
+
+	Complete  bool  // Whether the goal of the mode was reached
+	Ticks     int   // Game time played
+	LineTicks []int // Tick each line was cleared at
+	Pieces    int
+}
+
+// LineClearPayload describes a lock that cleared lines or spun a T piece in.
+type LineClearPayload struct {
+	Rows         []int // Cleared row indices from top to bottom, numbered before the stack collapsed
+	Lines        int
+	Type         tetris.ClearType
+	Combo        int // -1 when the lock cleared no lines
+	BackToBack   bool
+	PerfectClear bool
+	Points       int
+}
+
+// SettingsChangedPayload carries the settings to apply while the options scene edits them.
+type SettingsChangedPayload struct {
+	Settings settings.Settings
+}
+
+type LevelUpPayload struct {
+	Level int
+}
+
+// DangerPayload reports the stack rising near the top of the board, or falling back from it.
+type DangerPayload struct {
+	Danger bool
+}
+
+// PieceLockedPayload describes a piece that just locked into the stack.
+type PieceLockedPayload struct {
+	Piece        *tetris.Piece
+	HardDropRows int // Rows the piece fell when hard dropped, 0 for other locks
+}
+
+// LockEvents returns the events published for a piece lock: the lock itself,
+// then a line clear and a level up when they happened.
+func LockEvents(lock tetris.LockResult) []event.Event {
+	events := []event.Event{{Type: event.EventTypePieceLocked, Payload: PieceLockedPayload{
+		Piece:        lock.Piece,
+		HardDropRows: lock.HardDropRows,
+	}}}
+
+	if lock.ClearType() != tetris.ClearNone {
+		events = append(events, event.Event{Type: event.EventTypeLineClear, Payload: LineClearPayload{
+			Rows:         lock.Rows,
+			Lines:        lock.Lines,
+			Type:         lock.ClearType(),
+			Combo:        lock.Combo,
+			BackToBack:   lock.BackToBack,
+			PerfectClear: lock.PerfectClear,
+			Points:       lock.Points,
+		}})
+	}
+
+	if lock.LevelUp {
+		events = append(events, event.Event{Type: event.EventTypeLevelUp, Payload: LevelUpPayload{Level: lock.Level}})
+	}
+
+	return events
+}
@@ -0,0 +1,89 @@
+package gameevent
+
+import (
+	"testing"
+
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/tetris"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockEvents(t *testing.T) {
+	t.Parallel()
+
+	piece := tetris.NewPiece(tetris.ShapeI, 0, 16, 1)
+
+	tests := []struct {
+		name          string
+		lock          tetris.LockResult
+		expectedTypes []event.EventType
+	}{
+		{
+			name:          "plain lock",
+			lock:          tetris.LockResult{Piece: piece, Level: 1},
+			expectedTypes: []event.EventType{event.EventTypePieceLocked},
+		},
+		{
+			name: "line clear",
+			lock: tetris.LockResult{
+				LockScore: tetris.LockScore{Lines: 2, Combo: 1, Points: 350},
+				Piece:     piece,
+				Rows:      []int{18, 19},
+				Level:     1,
+			},
+			expectedTypes: []event.EventType{event.EventTypePieceLocked, event.EventTypeLineClear},
+		},
+		{
+			name: "T-spin without lines",
+			lock: tetris.LockResult{
+				LockScore: tetris.LockScore{TSpin: tetris.TSpinFull, Combo: -1, Points: 400},
+				Piece:     piece,
+				Level:     1,
+			},
+			expectedTypes: []event.EventType{event.EventTypePieceLocked, event.EventTypeLineClear},
+		},
+		{
+			name: "level up",
+			lock: tetris.LockResult{
+				LockScore: tetris.LockScore{Lines: 4, Points: 800},
+				Piece:     piece,
+				Rows:      []int{16, 17, 18, 19},
+				Level:     2,
+				LevelUp:   true,
+			},
+			expectedTypes: []event.EventType{event.EventTypePieceLocked, event.EventTypeLineClear, event.EventTypeLevelUp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			events := LockEvents(tt.lock)
+			types := make([]event.EventType, len(events))
+			for i, e := range events {
+				types[i] = e.Type
+			}
+			assert.Equal(t, tt.expectedTypes, types)
+		})
+	}
+}
+
+func TestLineClearPayload(t *testing.T) {
+	t.Parallel()
+
+	events := LockEvents(tetris.LockResult{
+		LockScore: tetris.LockScore{Lines: 2, TSpin: tetris.TSpinFull, Combo: 3, BackToBack: true, Points: 1950},
+		Rows:      []int{17, 19},
+		Level:     1,
+	})
+
+	assert.Equal(t, LineClearPayload{
+		Rows:       []int{17, 19},
+		Lines:      2,
+		Type:       tetris.ClearTSpin,
+		Combo:      3,
+		BackToBack: true,
+		Points:     1950,
+	}, events[1].Payload)
+}
@@ -0,0 +1,74 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// BenchmarkSongStartup compares decoding every song up front, as the audio
+// manager used to at startup, with opening the streams it now plays from.
+// held-B/op is how much heap the songs keep alive once they are loaded.
+func BenchmarkSongStartup(b *testing.B) {
+	b.Run("decode everything", func(b *testing.B) {
+		b.ReportAllocs()
+		load := func() any {
+			var decoded [][]byte
+			for _, raw := range songs {
+				data, err := decodeMP3(raw)
+				if err != nil {
+					b.Fatal(err)
+				}
+				decoded = append(decoded, data)
+			}
+			return decoded
+		}
+
+		for b.Loop() {
+			load()
+		}
+		b.ReportMetric(float64(retainedHeap(load)), "held-B/op")
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		// A player reads the start of a stream as soon as it is created, so
+		// each stream holds its first buffer of decoded audio like it would then
+		buf := make([]byte, 4096)
+		load := func() any {
+			var streams []io.Reader
+			for name := range songs {
+				stream, err := openSong(name)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := stream.Read(buf); err != nil && !errors.Is(err, io.EOF) {
+					b.Fatal(err)
+				}
+				streams = append(streams, stream)
+			}
+			return streams
+		}
+
+		for b.Loop() {
+			load()
+		}
+		b.ReportMetric(float64(retainedHeap(load)), "held-B/op")
+	})
+}
+
+// retainedHeap returns how many bytes of heap stay in use while what load
+// returns is kept alive.
+func retainedHeap(load func() any) int64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	loaded := load()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(loaded)
+
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log/slog"
 	"math/rand"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
@@ -16,147 +17,147 @@ type EffectPlayer interface {
 }
 
 type MusicPlayer interface {
-	StartPlaylist(names ...SongName)
+	PlayPlaylist(playlist Playlist)
+	SetDanger(danger bool)
+	SetTempo(tempo float64)
 }
 
 type AudioUpdater interface {
 	Update() error
 }
 
+// VolumeController sets the volume levels, each from 0 for silence to 1 for
+// full volume. Music and effects play at their own level times the master level.
+type VolumeController interface {
+	SetMasterVolume(volume float64)
+	SetMusicVolume(volume float64)
+	SetEffectsVolume(volume float64)
+	SetMuted(muted bool)
+}
+
 const samplingRate = 44100
 
+const defaultMusicVolume = 0.1
+
 type AudioManager struct {
 	audioContext *audio.Context
 
-	decodedSongs   map[SongName][]byte
-	decodedEffects map[EffectName][]byte
+	effectVariants map[EffectName][][]byte // Decoded effects at each pitch they can play at
 
-	musicPlayer   *audio.Player
-	effectPlayers map[EffectName]*audio.Player
+	effectVoices map[EffectName]*voicePool
 
-	currentSong SongName
-	playlist    []SongName
+	playlist  Playlist
+	queue     *playlistQueue
+	music     *track   // Song playing or fading in, nil when there is none
+	fadingOut []*track // Songs fading out under it
+	danger    bool
+	tempo     float64
+	rand      *rand.Rand
+
+	mixer mixer
 }
 
 func NewAudioManager() *AudioManager {
 	audioCtx := audio.NewContext(samplingRate)
 
-	decodedSongs := make(map[SongName][]byte)
-	for name, raw := range songs {
-		data, err := decodeMP3(raw)
-		if err != nil {
-			slog.Error("failed to decode audio", "subsystem", "audio", "song", name, "err", err)
-			panic(err)
-		}
-		decodedSongs[name] = data
-	}
-
-	decodedEffects := make(map[EffectName][]byte)
+	// Songs are streamed from their MP3s as they play, only the short effects are decoded up front
+	effectVariants := make(map[EffectName][][]byte)
+	effectVoices := make(map[EffectName]*voicePool)
 	for name, raw := range effects {
 		data, err := decodeMP3(raw)
 		if err != nil {
 			slog.Error("failed to decode audio", "subsystem", "audio", "effect", name, "err", err)
 			panic(err)
 		}
-		decodedEffects[name] = data
-	}
-
-	// For better effect use 4 players for each effect and overlap them.
-	effectPlayers := make(map[EffectName]*audio.Player)
-	for name, data := range decodedEffects {
-		p, err := audioCtx.NewPlayer(bytes.NewReader(data))
-		if err != nil {
-			slog.Error("failed to create effect player", "subsystem", "audio", "effect", name, "err", err)
-			panic(err)
-		}
-		effectPlayers[name] = p
+		options := effectOptions[name]
+		effectVariants[name] = pitchVariants(data, options.PitchVariation)
+		effectVoices[name] = newVoicePool(options.MaxVoices)
 	}
 
 	return &AudioManager{
-		audioContext:   audioCtx,
-		decodedSongs:   decodedSongs,
-		decodedEffects: decodedEffects,
+		audioContext: audioCtx,
 
-		effectPlayers: effectPlayers,
+		effectVariants: effectVariants,
+		effectVoices:   effectVoices,
+
+		tempo: 1,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+
+		mixer: newMixer(),
 	}
 }
 
 func (m *AudioManager) Update() error {
-	if m.musicPlayer != nil && !m.musicPlayer.IsPlaying() && len(m.playlist) > 1 {
-		m.PlayShuffle()
-	}
-
+	m.mixer.update()
+	m.updateMusic()
 	return nil
 }
 
-func (m *AudioManager) PlayShuffle() {
-	if len(m.playlist) == 0 {
-		return
-	}
+func (m *AudioManager) SetMasterVolume(volume float64) {
+	m.mixer.master = clampVolume(volume)
+	m.applyVolumes()
+}
 
-	var randomIndex int
-	for i := 0; i < 10; i++ {
-		randomIndex = rand.Intn(len(m.playlist))
-		if m.playlist[randomIndex] != m.currentSong {
-			break
-		}
-	}
+func (m *AudioManager) SetMusicVolume(volume float64) {
+	m.mixer.music = clampVolume(volume)
+	m.applyVolumes()
+}
 
-	m.PlaySong(m.playlist[randomIndex])
+func (m *AudioManager) SetEffectsVolume(volume float64) {
+	m.mixer.effects = clampVolume(volume)
+	m.applyVolumes()
 }
 
-func (m *AudioManager) PlaySong(name SongName) {
-	slog.Info("playing track", "subsystem", "audio", "song", name)
-	m.currentSong = name
+// SetMuted silences everything without losing the volume levels.
+func (m *AudioManager) SetMuted(muted bool) {
+	m.mixer.muted = muted
+	m.applyVolumes()
+}
 
-	if m.musicPlayer != nil {
-		m.musicPlayer.Pause()
+// applyVolumes sets the volume of every player, including the ones already playing.
+func (m *AudioManager) applyVolumes() {
+	m.applyMusicVolume()
+	for _, pool := range m.effectVoices {
+		pool.setVolume(m.mixer.effectVolume())
 	}
+}
 
-	if err := m.loadTrack(name); err != nil {
-		slog.Error("failed to load track", "subsystem", "audio", "song", name, "err", err)
-		panic(err)
-	}
-	if err := m.musicPlayer.Rewind(); err != nil {
-		slog.Error("failed to rewind track", "subsystem", "audio", "song", name, "err", err)
-		panic(err)
+// PlayEffect starts another copy of the effect, which overlaps the copies
+// already playing up to the effect's MaxVoices.
+func (m *AudioManager) PlayEffect(name EffectName) {
+	variants, ok := m.effectVariants[name]
+	if !ok {
+		return
 	}
-	m.musicPlayer.SetVolume(0.1)
-	m.musicPlayer.Play()
-}
+	options := effectOptions[name]
 
-func (m *AudioManager) StartPlaylist(names ...SongName) {
-	m.playlist = names
-	m.PlayShuffle()
-}
+	gain := 1 - m.rand.Float64()*options.VolumeVariation
+	p := m.audioContext.NewPlayerFromBytes(variants[m.rand.Intn(len(variants))])
+	p.SetVolume(m.mixer.effectVolume() * gain)
+	p.Play()
 
-func (m *AudioManager) loadTrack(name SongName) error {
-	var err error
-	if m.musicPlayer != nil {
-		err = m.musicPlayer.Close()
-		if err != nil {
-			return err
-		}
+	if stolen := m.effectVoices[name].add(p, gain); stolen > 0 {
+		slog.Debug("effect voice stolen", "subsystem", "audio", "effect", name)
 	}
 
-	m.musicPlayer, err = m.audioContext.NewPlayer(bytes.NewReader(m.decodedSongs[name]))
-	if err != nil {
-		return err
+	if duckingEffects[name] {
+		m.mixer.duck()
+		m.applyMusicVolume()
 	}
-
-	return nil
 }
 
-func (m *AudioManager) PlayEffect(name EffectName) {
-	p, ok := m.effectPlayers[name]
+// openSong returns a stream that decodes the song's MP3 while it plays, so
+// the song is never held in memory decoded.
+func openSong(name SongName) (*mp3.Stream, error) {
+	raw, ok := songs[name]
 	if !ok {
-		return
+		return nil, fmt.Errorf("unknown song %d", name)
 	}
-	if err := p.Rewind(); err != nil {
-		slog.Error("failed to rewind track", "subsystem", "audio", "effect", name, "err", err)
+	stream, err := mp3.DecodeWithSampleRate(samplingRate, bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode mp3 file: %w", err)
 	}
-
-	p.Play()
+	return stream, nil
 }
 
 func decodeMP3(raw []byte) ([]byte, error) {
@@ -0,0 +1,67 @@
+package audio
+
+// Ducking lowers the music for a moment while a loud effect plays: it holds
+// the music at duckVolume, then brings it back up over duckReleaseFrames.
+const (
+	duckVolume        = 0.35 // Share of the music volume left while ducked
+	duckHoldFrames    = 12
+	duckReleaseFrames = 18
+)
+
+// mixer works out the volume each player should play at from the master,
+// music and effect levels, the mute switch and ducking.
+type mixer struct {
+	master  float64
+	music   float64
+	effects float64
+	muted   bool
+
+	duckTimer int // Frames left of the current duck, hold and release together
+}
+
+func newMixer() mixer {
+	return mixer{master: 1, music: defaultMusicVolume, effects: 1}
+}
+
+func (m *mixer) musicVolume() float64 {
+	if m.muted {
+		return 0
+	}
+	return m.master * m.music * m.duckGain()
+}
+
+func (m *mixer) effectVolume() float64 {
+	if m.muted {
+		return 0
+	}
+	return m.master * m.effects
+}
+
+// duck starts ducking the music again, even when it is already ducked.
+func (m *mixer) duck() {
+	m.duckTimer = duckHoldFrames + duckReleaseFrames
+}
+
+// update moves ducking one frame on. The music picks up the new volume when
+// the crossfades are applied, every frame.
+func (m *mixer) update() {
+	if m.duckTimer > 0 {
+		m.duckTimer--
+	}
+}
+
+func (m *mixer) duckGain() float64 {
+	switch {
+	case m.duckTimer <= 0:
+		return 1
+	case m.duckTimer >= duckReleaseFrames:
+		return duckVolume
+	}
+	released := 1 - float64(m.duckTimer)/duckReleaseFrames
+	return duckVolume + (1-duckVolume)*released
+}
+
+// clampVolume keeps a volume level between silence and full volume.
+func clampVolume(volume float64) float64 {
+	return min(max(volume, 0), 1)
+}
@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMixerVolumes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mixer   mixer
+		music   float64
+		effects float64
+	}{
+		{name: "defaults", mixer: newMixer(), music: 0.1, effects: 1},
+		{name: "master scales both", mixer: mixer{master: 0.5, music: 0.4, effects: 0.8}, music: 0.2, effects: 0.4},
+		{name: "muted", mixer: mixer{master: 1, music: 1, effects: 1, muted: true}, music: 0, effects: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InDelta(t, tt.music, tt.mixer.musicVolume(), 1e-9)
+			assert.InDelta(t, tt.effects, tt.mixer.effectVolume(), 1e-9)
+		})
+	}
+}
+
+func TestMixerDucking(t *testing.T) {
+	t.Parallel()
+
+	m := mixer{master: 1, music: 1, effects: 1}
+	m.duck()
+	assert.InDelta(t, duckVolume, m.musicVolume(), 1e-9)
+	assert.InDelta(t, 1, m.effectVolume(), 1e-9, "effects are not ducked")
+
+	for range duckHoldFrames {
+		m.update()
+		assert.InDelta(t, duckVolume, m.musicVolume(), 1e-9, "music stays down while the duck holds")
+	}
+
+	last := m.musicVolume()
+	for range duckReleaseFrames {
+		m.update()
+		assert.Greater(t, m.musicVolume(), last, "music comes back up during the release")
+		last = m.musicVolume()
+	}
+	assert.InDelta(t, 1, m.musicVolume(), 1e-9)
+	m.update()
+	assert.InDelta(t, 1, m.musicVolume(), 1e-9)
+
+	m.duck()
+	m.muted = true
+	assert.Zero(t, m.musicVolume())
+}
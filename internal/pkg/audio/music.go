@@ -0,0 +1,237 @@
+package audio
+
+import (
+	"log/slog"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// crossfadeFrames is how long one song fades into the next.
+const crossfadeFrames = 90
+
+type PlaylistMode int
+
+const (
+	PlaylistLoop    PlaylistMode = iota // Plays the songs in order, over and over
+	PlaylistShuffle                     // Plays the songs in a new random order every time round
+	PlaylistOnce                        // Plays the songs in order, then stops
+)
+
+// Playlist is the music for one part of the game. Playlists are told apart by
+// name, so asking for the playlist that is already playing keeps it going.
+type Playlist struct {
+	Name  string
+	Songs []SongName
+	Mode  PlaylistMode
+}
+
+// playlistQueue picks the songs of a playlist in the order they play.
+type playlistQueue struct {
+	playlist Playlist
+	rand     *rand.Rand
+	order    []SongName
+	next     int
+	last     SongName
+}
+
+func newPlaylistQueue(playlist Playlist, rand *rand.Rand) *playlistQueue {
+	return &playlistQueue{playlist: playlist, rand: rand}
+}
+
+// pop returns the next song to play, or false when the playlist is over.
+func (q *playlistQueue) pop() (SongName, bool) {
+	if q.next >= len(q.order) {
+		if len(q.order) > 0 && q.playlist.Mode == PlaylistOnce {
+			return 0, false
+		}
+		q.refill()
+		if len(q.order) == 0 {
+			return 0, false
+		}
+	}
+
+	q.last = q.order[q.next]
+	q.next++
+	return q.last, true
+}
+
+// refill starts another round of the playlist. A shuffled round never starts
+// with the song that ended the last one.
+func (q *playlistQueue) refill() {
+	q.order = slices.Clone(q.playlist.Songs)
+	q.next = 0
+	if q.playlist.Mode != PlaylistShuffle {
+		return
+	}
+
+	q.rand.Shuffle(len(q.order), func(i, j int) {
+		q.order[i], q.order[j] = q.order[j], q.order[i]
+	})
+	if len(q.order) > 1 && q.order[0] == q.last {
+		swap := 1 + q.rand.Intn(len(q.order)-1)
+		q.order[0], q.order[swap] = q.order[swap], q.order[0]
+	}
+}
+
+// fade moves a gain in a straight line to its target.
+type fade struct {
+	gain   float64
+	target float64
+	step   float64
+}
+
+// to starts moving towards target, getting there in the given number of frames.
+func (f *fade) to(target float64, frames int) {
+	f.target = target
+	if frames <= 0 {
+		f.gain = target
+		return
+	}
+	f.step = (target - f.gain) / float64(frames)
+}
+
+func (f *fade) update() {
+	if f.done() {
+		return
+	}
+	f.gain += f.step
+	if (f.step > 0 && f.gain > f.target) || (f.step < 0 && f.gain < f.target) || f.step == 0 {
+		f.gain = f.target
+	}
+}
+
+func (f *fade) done() bool {
+	return f.gain == f.target
+}
+
+// track is one song being played, with its own gain for crossfading.
+type track struct {
+	song   SongName
+	player *audio.Player
+	stream *tempoStream
+	gain   fade
+	next   bool // The next song has been started
+}
+
+// PlayPlaylist crossfades into the playlist. When it is already playing it
+// keeps going, so the music carries on through pause and back.
+func (m *AudioManager) PlayPlaylist(playlist Playlist) {
+	if m.queue != nil && m.playlist.Name == playlist.Name {
+		return
+	}
+	slog.Info("playing playlist", "subsystem", "audio", "playlist", playlist.Name)
+
+	m.playlist = playlist
+	m.queue = newPlaylistQueue(playlist, m.rand)
+	m.danger = false
+	m.crossfadeTo(m.queue.pop())
+}
+
+// SetDanger switches to the danger song while the stack is near the top, and
+// back to the playlist once it is not.
+func (m *AudioManager) SetDanger(danger bool) {
+	if m.danger == danger || m.queue == nil {
+		return
+	}
+
+	m.danger = danger
+	if danger {
+		m.crossfadeTo(DangerSong, true)
+	} else {
+		m.crossfadeTo(m.queue.pop())
+	}
+}
+
+// SetTempo changes how fast the music plays, 1 being its recorded speed.
+func (m *AudioManager) SetTempo(tempo float64) {
+	m.tempo = min(max(tempo, 1/MaxTempo), MaxTempo)
+	for _, t := range m.tracks() {
+		t.stream.setTempo(m.tempo)
+	}
+}
+
+// crossfadeTo fades out the song that is playing while the given one fades
+// in. Without a song the music fades out.
+func (m *AudioManager) crossfadeTo(song SongName, ok bool) {
+	if m.music != nil {
+		m.music.gain.to(0, crossfadeFrames)
+		m.fadingOut = append(m.fadingOut, m.music)
+		m.music = nil
+	}
+	if !ok {
+		return
+	}
+
+	t, err := m.openTrack(song)
+	if err != nil {
+		slog.Error("failed to load track", "subsystem", "audio", "song", song, "err", err)
+		return
+	}
+	slog.Info("playing track", "subsystem", "audio", "song", song)
+
+	t.gain.to(1, crossfadeFrames)
+	m.music = t
+	m.applyMusicVolume()
+	t.player.Play()
+}
+
+func (m *AudioManager) openTrack(song SongName) (*track, error) {
+	stream, err := openSong(song)
+	if err != nil {
+		return nil, err
+	}
+
+	tempo := newTempoStream(stream, stream.Length())
+	tempo.setTempo(m.tempo)
+	player, err := m.audioContext.NewPlayer(tempo)
+	if err != nil {
+		return nil, err
+	}
+	return &track{song: song, player: player, stream: tempo}, nil
+}
+
+// updateMusic moves the crossfades on and starts the next song as the one
+// playing gets near its end, so they overlap.
+func (m *AudioManager) updateMusic() {
+	for _, t := range m.tracks() {
+		t.gain.update()
+	}
+
+	m.fadingOut = slices.DeleteFunc(m.fadingOut, func(t *track) bool {
+		if t.gain.done() || !t.player.IsPlaying() {
+			if err := t.player.Close(); err != nil {
+				slog.Error("failed to close track", "subsystem", "audio", "song", t.song, "err", err)
+			}
+			return true
+		}
+		return false
+	})
+
+	if m.music != nil && !m.music.next && m.music.stream.remaining() < crossfadeFrames*time.Second/60 {
+		m.music.next = true
+		if m.danger {
+			m.crossfadeTo(DangerSong, true)
+		} else if song, ok := m.queue.pop(); ok {
+			m.crossfadeTo(song, true)
+		}
+	}
+
+	m.applyMusicVolume()
+}
+
+// applyMusicVolume sets each song's volume from the mixer and its crossfade.
+func (m *AudioManager) applyMusicVolume() {
+	for _, t := range m.tracks() {
+		t.player.SetVolume(m.mixer.musicVolume() * t.gain.gain)
+	}
+}
+
+func (m *AudioManager) tracks() []*track {
+	if m.music == nil {
+		return m.fadingOut
+	}
+	return append(slices.Clone(m.fadingOut), m.music)
+}
@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// popAll returns up to n songs from the queue, stopping when it runs out.
+func popAll(q *playlistQueue, n int) []SongName {
+	var played []SongName
+	for range n {
+		song, ok := q.pop()
+		if !ok {
+			break
+		}
+		played = append(played, song)
+	}
+	return played
+}
+
+func TestPlaylistQueue(t *testing.T) {
+	t.Parallel()
+
+	const a, b, c = SongName(1), SongName(2), SongName(3)
+
+	tests := []struct {
+		name     string
+		playlist Playlist
+		expected []SongName
+	}{
+		{
+			name:     "loop plays in order over and over",
+			playlist: Playlist{Songs: []SongName{a, b, c}, Mode: PlaylistLoop},
+			expected: []SongName{a, b, c, a, b, c, a},
+		},
+		{
+			name:     "once stops at the end",
+			playlist: Playlist{Songs: []SongName{a, b}, Mode: PlaylistOnce},
+			expected: []SongName{a, b},
+		},
+		{
+			name:     "single song loops",
+			playlist: Playlist{Songs: []SongName{c}, Mode: PlaylistShuffle},
+			expected: []SongName{c, c, c, c, c, c, c},
+		},
+		{
+			name:     "empty playlist plays nothing",
+			playlist: Playlist{Mode: PlaylistLoop},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newPlaylistQueue(tt.playlist, rand.New(rand.NewSource(1)))
+			assert.Equal(t, tt.expected, popAll(q, 7))
+		})
+	}
+}
+
+func TestPlaylistQueueShuffle(t *testing.T) {
+	t.Parallel()
+
+	songs := []SongName{1, 2, 3, 4}
+	for seed := range int64(20) {
+		q := newPlaylistQueue(Playlist{Songs: songs, Mode: PlaylistShuffle}, rand.New(rand.NewSource(seed)))
+		played := popAll(q, 40)
+
+		for round := 0; round < len(played); round += len(songs) {
+			assert.ElementsMatch(t, songs, played[round:round+len(songs)], "every round plays each song once")
+		}
+		for i := 1; i < len(played); i++ {
+			assert.NotEqual(t, played[i-1], played[i], "no song plays twice in a row")
+		}
+	}
+}
+
+func TestFade(t *testing.T) {
+	t.Parallel()
+
+	var f fade
+	f.to(1, 4)
+	var gains []float64
+	for !f.done() {
+		f.update()
+		gains = append(gains, f.gain)
+	}
+	assert.Equal(t, []float64{0.25, 0.5, 0.75, 1}, gains)
+
+	f.to(0, 2)
+	f.update()
+	f.to(1, 0)
+	assert.True(t, f.done(), "zero frames jumps straight to the target")
+	assert.Equal(t, 1.0, f.gain)
+}
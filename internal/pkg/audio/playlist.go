@@ -22,7 +22,28 @@ var songs map[SongName][]byte = map[SongName][]byte{
 	ArcadeBeat:         assets.ArcadeBeat,
 }
 
+// Music for each part of the game.
+var (
+	MenuPlaylist     = Playlist{Name: "menu", Songs: []SongName{ReturnOfThe8BitEra}, Mode: PlaylistLoop}
+	GameplayPlaylist = Playlist{Name: "gameplay", Songs: []SongName{ArcadeBeat, ReturnOfThe8BitEra}, Mode: PlaylistShuffle}
+	GameOverPlaylist = Playlist{Name: "game over", Songs: []SongName{ReturnOfThe8BitEra}, Mode: PlaylistOnce}
+)
+
+// DangerSong loops in place of the playlist while the stack is near the top.
+const DangerSong = ArcadeBeat
+
 var effects map[EffectName][]byte = map[EffectName][]byte{
 	ExplosionEffect: assets.ExplosionEffect,
 	BipEffect:       assets.BipEffect,
 }
+
+var effectOptions = map[EffectName]EffectOptions{
+	ExplosionEffect: {MaxVoices: 2, VolumeVariation: 0.1},
+	// Played on every move, so DAS repeats need enough voices and a little variety
+	BipEffect: {MaxVoices: 6, PitchVariation: 0.04, VolumeVariation: 0.2},
+}
+
+// duckingEffects are loud enough that the music ducks while they play.
+var duckingEffects = map[EffectName]bool{
+	ExplosionEffect: true,
+}
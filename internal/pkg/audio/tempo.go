@@ -0,0 +1,132 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Music speeds up by levelTempoStep for every level, up to MaxTempo.
+const (
+	levelTempoStep = 0.02
+	MaxTempo       = 1.3
+)
+
+const (
+	pcmFrameSize   = 4 // Two channels of 16-bit samples
+	tempoChunkSize = 16 * 1024
+)
+
+// LevelTempo returns how fast the music plays at a level, 1 being its recorded speed.
+func LevelTempo(level int) float64 {
+	return min(1+levelTempoStep*float64(max(level-1, 0)), MaxTempo)
+}
+
+// tempoStream plays 16-bit stereo PCM from src faster or slower, which raises
+// or lowers the pitch with it, the way arcade music speeds up. The player
+// reads it from its own goroutine, so the tempo can change while it plays.
+type tempoStream struct {
+	src    io.ReadSeeker
+	length int64 // Bytes in src, 0 when unknown
+
+	tempo  atomic.Uint64 // math.Float64bits of the tempo
+	srcPos atomic.Int64  // Bytes read from src so far
+
+	buf []byte  // Frames read from src and not yet played past
+	pos float64 // Position in buf, in frames
+	eof bool
+}
+
+func newTempoStream(src io.ReadSeeker, length int64) *tempoStream {
+	s := &tempoStream{src: src, length: length}
+	s.setTempo(1)
+	return s
+}
+
+func (s *tempoStream) setTempo(tempo float64) {
+	s.tempo.Store(math.Float64bits(tempo))
+}
+
+func (s *tempoStream) getTempo() float64 {
+	return math.Float64frombits(s.tempo.Load())
+}
+
+// remaining returns roughly how long the stream plays for at its current tempo.
+func (s *tempoStream) remaining() time.Duration {
+	if s.length <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	seconds := float64(s.length-s.srcPos.Load()) / (samplingRate * pcmFrameSize) / s.getTempo()
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (s *tempoStream) Read(p []byte) (int, error) {
+	tempo := s.getTempo()
+
+	n := 0
+	for n+pcmFrameSize <= len(p) {
+		frame := int(s.pos)
+		// Interpolating needs the frame after this one as well
+		if (frame+2)*pcmFrameSize > len(s.buf) {
+			if !s.fill() {
+				break
+			}
+			continue
+		}
+
+		weight := s.pos - float64(frame)
+		for channel := range 2 {
+			at := frame*pcmFrameSize + channel*2
+			current := float64(int16(binary.LittleEndian.Uint16(s.buf[at:])))
+			next := float64(int16(binary.LittleEndian.Uint16(s.buf[at+pcmFrameSize:])))
+			value := current*(1-weight) + next*weight
+			binary.LittleEndian.PutUint16(p[n+channel*2:], uint16(int16(math.Round(value))))
+		}
+		n += pcmFrameSize
+		s.pos += tempo
+	}
+
+	if n == 0 && s.eof {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// fill drops the frames already played past and reads more from src.
+// Returns false once src has nothing left.
+func (s *tempoStream) fill() bool {
+	if s.eof {
+		return false
+	}
+
+	played := int(s.pos)
+	s.buf = s.buf[:copy(s.buf, s.buf[min(played*pcmFrameSize, len(s.buf)):])]
+	s.pos -= float64(played)
+
+	start := len(s.buf)
+	s.buf = slices.Grow(s.buf, tempoChunkSize)[:start+tempoChunkSize]
+	read, err := io.ReadFull(s.src, s.buf[start:])
+	s.buf = s.buf[:start+read]
+	s.srcPos.Add(int64(read))
+	if err != nil {
+		s.eof = true
+	}
+	return read > 0
+}
+
+// Seek moves to an offset in src, so offsets are in recorded time rather
+// than played time. It is what rewinding the player uses.
+func (s *tempoStream) Seek(offset int64, whence int) (int64, error) {
+	pos, err := s.src.Seek(offset, whence)
+	if err != nil {
+		return 0, err
+	}
+	s.srcPos.Store(pos)
+	s.buf = s.buf[:0]
+	s.pos = 0
+	s.eof = false
+	return pos, nil
+}
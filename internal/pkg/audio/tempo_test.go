@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelTempo(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1.0, LevelTempo(0))
+	assert.Equal(t, 1.0, LevelTempo(1))
+	assert.InDelta(t, 1.1, LevelTempo(6), 1e-9)
+	assert.Equal(t, MaxTempo, LevelTempo(50))
+}
+
+func TestTempoStream(t *testing.T) {
+	t.Parallel()
+
+	samples := make([]int16, 1000)
+	for i := range samples {
+		samples[i] = int16(i)
+	}
+	pcm := stereo(samples...)
+
+	tests := []struct {
+		name   string
+		tempo  float64
+		frames int
+	}{
+		{name: "recorded speed", tempo: 1, frames: 999},
+		{name: "double speed", tempo: 2, frames: 500},
+		{name: "half speed", tempo: 0.5, frames: 1998},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTempoStream(bytes.NewReader(pcm), int64(len(pcm)))
+			s.setTempo(tt.tempo)
+			out, err := io.ReadAll(s)
+			require.NoError(t, err)
+			assert.Equal(t, tt.frames*pcmFrameSize, len(out))
+			assert.Equal(t, resample(pcm, tt.tempo)[:len(out)], out, "plays like the resampled PCM")
+		})
+	}
+}
+
+func TestTempoStreamRewind(t *testing.T) {
+	t.Parallel()
+
+	pcm := stereo(make([]int16, samplingRate)...) // One second
+	s := newTempoStream(bytes.NewReader(pcm), int64(len(pcm)))
+	s.setTempo(2)
+	assert.InDelta(t, 500*time.Millisecond, s.remaining(), float64(time.Millisecond))
+
+	_, err := io.ReadAll(s)
+	require.NoError(t, err)
+	assert.Zero(t, s.remaining())
+
+	_, err = s.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	assert.InDelta(t, 500*time.Millisecond, s.remaining(), float64(time.Millisecond))
+	out, err := io.ReadAll(s)
+	require.NoError(t, err)
+	assert.NotEmpty(t, out)
+}
@@ -0,0 +1,121 @@
+package audio
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"slices"
+)
+
+// EffectOptions tunes how copies of an effect overlap and vary.
+type EffectOptions struct {
+	MaxVoices       int     // Copies that can play at once; a new one cuts off the oldest
+	PitchVariation  float64 // Pitch changes at random by up to this share either way, 0 for none
+	VolumeVariation float64 // Volume drops at random by up to this share, 0 for none
+}
+
+// pitchSteps is how many pitches between the lowest and highest variation are
+// decoded ahead, so playing an effect never resamples it.
+const pitchSteps = 5
+
+// voice is one playing copy of an effect.
+type voice interface {
+	IsPlaying() bool
+	SetVolume(volume float64)
+	Close() error
+}
+
+type playingVoice struct {
+	voice
+	gain float64 // Share of the effect volume this copy plays at
+}
+
+// voicePool keeps the copies of one effect that are playing, oldest first.
+type voicePool struct {
+	maxVoices int
+	voices    []playingVoice
+}
+
+func newVoicePool(maxVoices int) *voicePool {
+	return &voicePool{maxVoices: max(maxVoices, 1)}
+}
+
+// add starts tracking a new copy, cutting off the oldest copies when the
+// pool is full. Returns how many copies were cut off.
+func (p *voicePool) add(v voice, gain float64) int {
+	p.reap()
+
+	stolen := max(len(p.voices)-p.maxVoices+1, 0)
+	for _, old := range p.voices[:stolen] {
+		if err := old.Close(); err != nil {
+			slog.Error("failed to close effect player", "subsystem", "audio", "err", err)
+		}
+	}
+	p.voices = append(p.voices[stolen:], playingVoice{voice: v, gain: gain})
+	return stolen
+}
+
+// reap forgets the copies that have finished playing.
+func (p *voicePool) reap() {
+	p.voices = slices.DeleteFunc(p.voices, func(v playingVoice) bool {
+		if v.IsPlaying() {
+			return false
+		}
+		if err := v.Close(); err != nil {
+			slog.Error("failed to close effect player", "subsystem", "audio", "err", err)
+		}
+		return true
+	})
+}
+
+// setVolume changes the volume of every copy that is playing.
+func (p *voicePool) setVolume(volume float64) {
+	for _, v := range p.voices {
+		v.SetVolume(volume * v.gain)
+	}
+}
+
+// pitchVariants returns the effect at pitchSteps pitches spread evenly across
+// the variation, or only the original when there is no variation.
+func pitchVariants(pcm []byte, variation float64) [][]byte {
+	if variation <= 0 {
+		return [][]byte{pcm}
+	}
+
+	variants := make([][]byte, pitchSteps)
+	for i := range variants {
+		ratio := 1 - variation + 2*variation*float64(i)/(pitchSteps-1)
+		variants[i] = resample(pcm, ratio)
+	}
+	return variants
+}
+
+// resample plays 16-bit stereo PCM ratio times faster, which raises its pitch
+// by the same ratio, interpolating linearly between the original samples.
+func resample(pcm []byte, ratio float64) []byte {
+	const frameSize = 4 // Two channels of 16-bit samples
+
+	frames := len(pcm) / frameSize
+	if frames == 0 || ratio <= 0 {
+		return pcm
+	}
+
+	sample := func(frame, channel int) float64 {
+		return float64(int16(binary.LittleEndian.Uint16(pcm[frame*frameSize+channel*2:])))
+	}
+
+	outFrames := int(float64(frames) / ratio)
+	out := make([]byte, outFrames*frameSize)
+	for i := range outFrames {
+		pos := float64(i) * ratio
+		frame := int(pos)
+		next := min(frame+1, frames-1)
+		weight := pos - float64(frame)
+
+		for channel := range 2 {
+			value := sample(frame, channel)*(1-weight) + sample(next, channel)*weight
+			binary.LittleEndian.PutUint16(out[i*frameSize+channel*2:], uint16(int16(math.Round(value))))
+		}
+	}
+	return out
+}
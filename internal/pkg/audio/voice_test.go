@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVoice struct {
+	playing bool
+	closed  bool
+	volume  float64
+}
+
+func (v *fakeVoice) IsPlaying() bool          { return v.playing && !v.closed }
+func (v *fakeVoice) SetVolume(volume float64) { v.volume = volume }
+func (v *fakeVoice) Close() error {
+	v.closed = true
+	return nil
+}
+
+func TestVoicePool(t *testing.T) {
+	t.Parallel()
+
+	pool := newVoicePool(2)
+	first := &fakeVoice{playing: true}
+	second := &fakeVoice{playing: true}
+	third := &fakeVoice{playing: true}
+
+	assert.Zero(t, pool.add(first, 1))
+	assert.Zero(t, pool.add(second, 0.5))
+	assert.Equal(t, 1, pool.add(third, 1), "a full pool cuts off the oldest voice")
+	assert.True(t, first.closed)
+	assert.False(t, second.closed)
+
+	pool.setVolume(0.8)
+	assert.InDelta(t, 0.4, second.volume, 1e-9, "each voice keeps its own gain")
+	assert.InDelta(t, 0.8, third.volume, 1e-9)
+
+	second.playing = false
+	fourth := &fakeVoice{playing: true}
+	assert.Zero(t, pool.add(fourth, 1), "finished voices make room first")
+	assert.True(t, second.closed)
+	assert.False(t, third.closed)
+}
+
+// stereo builds 16-bit stereo PCM with both channels playing the given samples.
+func stereo(samples ...int16) []byte {
+	pcm := make([]byte, 0, len(samples)*4)
+	for _, s := range samples {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(s))
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(s))
+	}
+	return pcm
+}
+
+func TestResample(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ratio    float64
+		expected []byte
+	}{
+		{name: "same pitch", ratio: 1, expected: stereo(0, 100, -100, 200)},
+		{name: "octave up skips samples", ratio: 2, expected: stereo(0, -100)},
+		{name: "octave down interpolates", ratio: 0.5, expected: stereo(0, 50, 100, 0, -100, 50, 200, 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, resample(stereo(0, 100, -100, 200), tt.ratio))
+		})
+	}
+}
+
+func TestPitchVariants(t *testing.T) {
+	t.Parallel()
+
+	pcm := stereo(make([]int16, 100)...)
+	assert.Equal(t, [][]byte{pcm}, pitchVariants(pcm, 0))
+
+	variants := pitchVariants(pcm, 0.2)
+	require.Len(t, variants, pitchSteps)
+	assert.Len(t, variants[0], 125*4, "lowest pitch plays longest")
+	assert.Len(t, variants[pitchSteps/2], 100*4)
+	assert.Len(t, variants[pitchSteps-1], 83*4)
+}
@@ -0,0 +1,28 @@
+// Package datadir locates the files the game saves, all kept together in one
+// directory in the player's home so they follow the player whichever
+// directory the game is started from.
+package datadir
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+const dirName = ".ebitris"
+
+// Path returns where the named file or directory is saved. Without a home
+// directory it falls back to the working directory.
+func Path(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		slog.Warn("no home directory, saving to the working directory", "subsystem", "datadir", "err", err)
+	}
+	return filepath.Join(home, dirName, name)
+}
+
+// LegacyPath returns where the named file was saved before the data directory
+// moved to the home directory, relative to the working directory.
+func LegacyPath(name string) string {
+	return filepath.Join(dirName, name)
+}
@@ -0,0 +1,20 @@
+package datadir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(home, ".ebitris", "scores.json"), Path("scores.json"))
+	assert.Equal(t, filepath.Dir(Path("scores.json")), filepath.Dir(Path("replays")), "everything is saved together")
+}
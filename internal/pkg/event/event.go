@@ -8,15 +8,22 @@ const (
 	EventTypeGameOver
 
 	EventTypeGoBack
+	EventTypeModeSelect
 	EventTypeStartGame
 	EventTypeMainMenu
 	EventTypeScoreboard
+	EventTypeWatchReplay
+	EventTypeControls
+	EventTypeOptions
+	EventTypeSettingsChanged
 
 	EventTypePause
 	EventTypeQuit
 
 	EventTypeBlockPlaced
 	EventTypeBlockMovedByPlayer
+	EventTypePieceLocked
+	EventTypeDanger
 )
 
 type Emitter interface {
@@ -35,9 +42,3 @@ type EmitterSubscriber interface {
 type Dispatcher interface {
 	Dispatch()
 }
-
-type GameOverPayload struct {
-	Score int
-	Lines int
-	Level int
-}
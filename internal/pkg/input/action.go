@@ -0,0 +1,123 @@
+package input
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Action is a player command that can be bound to one or more keys.
+type Action int
+
+const (
+	ActionMoveLeft Action = iota
+	ActionMoveRight
+	ActionSoftDrop
+	ActionHardDrop
+	ActionRotateCW
+	ActionRotateCCW
+	ActionHold
+	ActionPause
+
+	ActionMenuUp
+	ActionMenuDown
+	ActionConfirm
+	ActionBack
+
+	actionCount
+)
+
+var actionNames = [actionCount]string{
+	ActionMoveLeft:  "MoveLeft",
+	ActionMoveRight: "MoveRight",
+	ActionSoftDrop:  "SoftDrop",
+	ActionHardDrop:  "HardDrop",
+	ActionRotateCW:  "RotateCW",
+	ActionRotateCCW: "RotateCCW",
+	ActionHold:      "Hold",
+	ActionPause:     "Pause",
+	ActionMenuUp:    "MenuUp",
+	ActionMenuDown:  "MenuDown",
+	ActionConfirm:   "Confirm",
+	ActionBack:      "Back",
+}
+
+// Actions returns every action in the order they are listed to players.
+func Actions() []Action {
+	actions := make([]Action, actionCount)
+	for i := range actions {
+		actions[i] = Action(i)
+	}
+	return actions
+}
+
+func (a Action) String() string {
+	if a < 0 || a >= actionCount {
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+	return actionNames[a]
+}
+
+// MarshalText implements encoding.TextMarshaler, so bindings are saved with action names.
+func (a Action) MarshalText() ([]byte, error) {
+	if a < 0 || a >= actionCount {
+		return nil, fmt.Errorf("unknown action %d", int(a))
+	}
+	return []byte(actionNames[a]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Action) UnmarshalText(text []byte) error {
+	for i, name := range actionNames {
+		if name == string(text) {
+			*a = Action(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %q", string(text))
+}
+
+// Bindings maps each action to the keys that trigger it.
+type Bindings map[Action][]ebiten.Key
+
+func DefaultBindings() Bindings {
+	return Bindings{
+		ActionMoveLeft:  {ebiten.KeyLeft},
+		ActionMoveRight: {ebiten.KeyRight},
+		ActionSoftDrop:  {ebiten.KeyDown},
+		ActionHardDrop:  {ebiten.KeySpace},
+		ActionRotateCW:  {ebiten.KeyUp, ebiten.KeyX},
+		ActionRotateCCW: {ebiten.KeyZ},
+		ActionHold:      {ebiten.KeyC, ebiten.KeyShift},
+		ActionPause:     {ebiten.KeyEscape, ebiten.KeyP},
+		ActionMenuUp:    {ebiten.KeyUp},
+		ActionMenuDown:  {ebiten.KeyDown},
+		ActionConfirm:   {ebiten.KeyEnter},
+		ActionBack:      {ebiten.KeyEscape},
+	}
+}
+
+// Clone returns a copy that can be changed without affecting b.
+func (b Bindings) Clone() Bindings {
+	clone := maps.Clone(b)
+	for action, keys := range clone {
+		clone[action] = slices.Clone(keys)
+	}
+	return clone
+}
+
+// withDefaults fills in the default keys for every action without a binding.
+func (b Bindings) withDefaults() Bindings {
+	merged := b.Clone()
+	if merged == nil {
+		merged = Bindings{}
+	}
+	for action, keys := range DefaultBindings() {
+		if len(merged[action]) == 0 {
+			merged[action] = keys
+		}
+	}
+	return merged
+}
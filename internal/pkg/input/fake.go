@@ -0,0 +1,147 @@
+package input
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type fakeGamepad struct {
+	standard  bool
+	connected bool
+	held      map[ebiten.StandardGamepadButton]bool
+	pressed   map[ebiten.StandardGamepadButton]bool
+	last      map[ebiten.StandardGamepadButton]bool
+}
+
+// FakeSource is an InputSource that tests script one frame at a time, so
+// scenes can be driven by go test without opening a window. Keys are given
+// to Frame; gamepads are plugged in with Connect and held with SetButtons,
+// and those changes show up on the next Frame.
+type FakeSource struct {
+	pressed map[ebiten.Key]bool
+	last    map[ebiten.Key]bool
+
+	pads          map[ebiten.GamepadID]*fakeGamepad
+	connecting    []ebiten.GamepadID
+	disconnecting []ebiten.GamepadID
+	connected     []ebiten.GamepadID
+	disconnected  []ebiten.GamepadID
+}
+
+func NewFakeSource() *FakeSource {
+	return &FakeSource{
+		pressed: map[ebiten.Key]bool{},
+		last:    map[ebiten.Key]bool{},
+		pads:    map[ebiten.GamepadID]*fakeGamepad{},
+	}
+}
+
+// Frame moves to the next frame with exactly the given keys held.
+func (f *FakeSource) Frame(keys ...ebiten.Key) {
+	f.last = f.pressed
+	f.pressed = map[ebiten.Key]bool{}
+	for _, key := range keys {
+		f.pressed[key] = true
+	}
+
+	f.connected, f.connecting = f.connecting, nil
+	for _, id := range f.connected {
+		f.pads[id].connected = true
+	}
+	f.disconnected, f.disconnecting = f.disconnecting, nil
+	for _, id := range f.disconnected {
+		delete(f.pads, id)
+	}
+
+	for _, pad := range f.pads {
+		pad.last = pad.pressed
+		pad.pressed = maps.Clone(pad.held)
+	}
+}
+
+// Connect plugs in a gamepad on the next frame.
+func (f *FakeSource) Connect(id ebiten.GamepadID, standard bool) {
+	f.pads[id] = &fakeGamepad{standard: standard}
+	f.connecting = append(f.connecting, id)
+}
+
+// Disconnect unplugs a gamepad on the next frame.
+func (f *FakeSource) Disconnect(id ebiten.GamepadID) {
+	f.disconnecting = append(f.disconnecting, id)
+}
+
+// SetButtons holds exactly the given buttons on a gamepad from the next frame on.
+func (f *FakeSource) SetButtons(id ebiten.GamepadID, buttons ...ebiten.StandardGamepadButton) {
+	pad, ok := f.pads[id]
+	if !ok {
+		return
+	}
+	pad.held = map[ebiten.StandardGamepadButton]bool{}
+	for _, button := range buttons {
+		pad.held[button] = true
+	}
+}
+
+func (f *FakeSource) IsKeyPressed(key ebiten.Key) bool {
+	return f.pressed[key]
+}
+
+func (f *FakeSource) IsKeyJustPressed(key ebiten.Key) bool {
+	return f.pressed[key] && !f.last[key]
+}
+
+func (f *FakeSource) AppendJustPressedKeys(keys []ebiten.Key) []ebiten.Key {
+	for _, key := range slices.Sorted(maps.Keys(f.pressed)) {
+		if f.IsKeyJustPressed(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (f *FakeSource) AppendGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID {
+	for _, id := range slices.Sorted(maps.Keys(f.pads)) {
+		if f.pads[id].connected {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (f *FakeSource) AppendJustConnectedGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID {
+	return append(ids, f.connected...)
+}
+
+func (f *FakeSource) IsGamepadJustDisconnected(id ebiten.GamepadID) bool {
+	return slices.Contains(f.disconnected, id)
+}
+
+func (f *FakeSource) IsStandardGamepadLayoutAvailable(id ebiten.GamepadID) bool {
+	pad := f.pad(id)
+	return pad != nil && pad.standard
+}
+
+func (f *FakeSource) IsStandardGamepadButtonPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	pad := f.pad(id)
+	return pad != nil && pad.pressed[button]
+}
+
+func (f *FakeSource) IsStandardGamepadButtonJustPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	pad := f.pad(id)
+	return pad != nil && pad.pressed[button] && !pad.last[button]
+}
+
+func (f *FakeSource) GamepadName(id ebiten.GamepadID) string {
+	return "fake"
+}
+
+// pad returns the gamepad if it is connected, or nil.
+func (f *FakeSource) pad(id ebiten.GamepadID) *fakeGamepad {
+	pad, ok := f.pads[id]
+	if !ok || !pad.connected {
+		return nil
+	}
+	return pad
+}
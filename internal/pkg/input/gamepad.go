@@ -0,0 +1,88 @@
+package input
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GamepadSource reports connected gamepads and their buttons in the standard layout.
+type GamepadSource interface {
+	AppendGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID
+	AppendJustConnectedGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID
+	IsGamepadJustDisconnected(id ebiten.GamepadID) bool
+	IsStandardGamepadLayoutAvailable(id ebiten.GamepadID) bool
+	IsStandardGamepadButtonPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool
+	IsStandardGamepadButtonJustPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool
+	GamepadName(id ebiten.GamepadID) string
+}
+
+// GamepadBindings maps each action to the standard layout buttons that trigger it.
+type GamepadBindings map[Action][]ebiten.StandardGamepadButton
+
+func DefaultGamepadBindings() GamepadBindings {
+	return GamepadBindings{
+		ActionMoveLeft:  {ebiten.StandardGamepadButtonLeftLeft},
+		ActionMoveRight: {ebiten.StandardGamepadButtonLeftRight},
+		ActionSoftDrop:  {ebiten.StandardGamepadButtonLeftBottom},
+		ActionHardDrop:  {ebiten.StandardGamepadButtonLeftTop},
+		ActionRotateCW:  {ebiten.StandardGamepadButtonRightBottom, ebiten.StandardGamepadButtonRightTop},
+		ActionRotateCCW: {ebiten.StandardGamepadButtonRightRight, ebiten.StandardGamepadButtonRightLeft},
+		ActionHold:      {ebiten.StandardGamepadButtonFrontTopLeft, ebiten.StandardGamepadButtonFrontTopRight},
+		ActionPause:     {ebiten.StandardGamepadButtonCenterRight},
+		ActionMenuUp:    {ebiten.StandardGamepadButtonLeftTop},
+		ActionMenuDown:  {ebiten.StandardGamepadButtonLeftBottom},
+		ActionConfirm:   {ebiten.StandardGamepadButtonRightBottom},
+		ActionBack:      {ebiten.StandardGamepadButtonRightRight},
+	}
+}
+
+// updateGamepads picks up gamepads plugged in or out since the last frame.
+// Only gamepads with a standard layout mapping are read.
+func (im *InputManager) updateGamepads() {
+	for _, id := range im.gamepads {
+		if im.source.IsGamepadJustDisconnected(id) {
+			slog.Info("gamepad disconnected", "subsystem", "input", "id", id)
+		}
+	}
+
+	for _, id := range im.source.AppendJustConnectedGamepadIDs(nil) {
+		if im.source.IsStandardGamepadLayoutAvailable(id) {
+			slog.Info("gamepad connected", "subsystem", "input", "id", id, "name", im.source.GamepadName(id))
+		} else {
+			slog.Warn("gamepad has no standard layout, ignoring it", "subsystem", "input", "id", id, "name", im.source.GamepadName(id))
+		}
+	}
+
+	im.gamepads = slices.DeleteFunc(im.source.AppendGamepadIDs(im.gamepads[:0]), func(id ebiten.GamepadID) bool {
+		return !im.source.IsStandardGamepadLayoutAvailable(id)
+	})
+}
+
+// Gamepads returns the connected gamepads that are read for actions.
+func (im *InputManager) Gamepads() []ebiten.GamepadID {
+	return slices.Clone(im.gamepads)
+}
+
+func (im *InputManager) isButtonJustPressed(action Action) bool {
+	for _, id := range im.gamepads {
+		for _, button := range im.padBindings[action] {
+			if im.source.IsStandardGamepadButtonJustPressed(id, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (im *InputManager) isButtonPressed(action Action) bool {
+	for _, id := range im.gamepads {
+		for _, button := range im.padBindings[action] {
+			if im.source.IsStandardGamepadButtonPressed(id, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGamepadHotPlug(t *testing.T) {
+	t.Parallel()
+
+	source := NewFakeSource()
+	im := NewInputManager(source, DefaultSettings(), DefaultBindings())
+
+	source.Frame()
+	im.Update()
+	assert.Empty(t, im.Gamepads())
+
+	source.Connect(0, true)
+	source.Connect(1, false)
+	source.Frame()
+	im.Update()
+	assert.Equal(t, []ebiten.GamepadID{0}, im.Gamepads(), "gamepads without a standard layout are ignored")
+
+	source.SetButtons(0, ebiten.StandardGamepadButtonCenterRight)
+	source.SetButtons(1, ebiten.StandardGamepadButtonRightBottom)
+	source.Frame()
+	im.Update()
+	assert.True(t, im.IsActionJustPressed(ActionPause))
+	assert.False(t, im.IsActionJustPressed(ActionConfirm))
+
+	source.Disconnect(0)
+	source.Frame()
+	im.Update()
+	assert.Empty(t, im.Gamepads())
+	assert.False(t, im.IsActionPressed(ActionPause))
+}
+
+func TestGamepadUsesKeyRepeat(t *testing.T) {
+	t.Parallel()
+
+	source := NewFakeSource()
+	im := NewInputManager(source, Settings{DAS: 3, ARR: 2}, DefaultBindings())
+	source.Connect(2, true)
+	source.SetButtons(2, ebiten.StandardGamepadButtonLeftLeft)
+
+	counts := make([]int, 8)
+	for i := range counts {
+		source.Frame()
+		im.Update()
+		counts[i] = im.Repeats(ActionMoveLeft, 10)
+	}
+	assert.Equal(t, []int{1, 0, 0, 1, 0, 1, 0, 1}, counts)
+}
+
+func TestGamepadAndKeyboardTogether(t *testing.T) {
+	t.Parallel()
+
+	source := NewFakeSource()
+	im := NewInputManager(source, DefaultSettings(), DefaultBindings())
+	source.Connect(0, true)
+	source.Frame()
+	im.Update()
+
+	source.Frame(ebiten.KeyEnter)
+	assert.True(t, im.IsActionJustPressed(ActionConfirm))
+
+	source.SetButtons(0, ebiten.StandardGamepadButtonRightBottom)
+	source.Frame()
+	assert.True(t, im.IsActionJustPressed(ActionConfirm))
+	assert.True(t, im.IsActionJustPressed(ActionRotateCW))
+	assert.False(t, im.IsActionJustPressed(ActionBack))
+}
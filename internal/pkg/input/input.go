@@ -2,52 +2,174 @@ package input
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
-type InputManager struct {
-	keyHoldTime map[ebiten.Key]int
-}
-
 const (
-	InitialDelay = 15 // Frames before repeat starts
-	RepeatDelay  = 3  // Frames between repeats
+	DefaultDAS            = 15 // Frames before repeat starts
+	DefaultARR            = 3  // Frames between repeats
+	DefaultSoftDropFactor = 20
 )
 
-func NewInputManager() *InputManager {
+// Settings tunes how held keys repeat. The zero value repeats every frame
+// without any delay.
+type Settings struct {
+	DAS            int  // Delayed auto shift: frames a key is held before it starts repeating
+	ARR            int  // Auto repeat rate: frames between repeats, 0 repeats all the way at once
+	SoftDropFactor int  // How many times faster than gravity a held soft drop moves the piece
+	KeepDASCharge  bool // Whether a charged key keeps repeating for the next piece after a lock
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		DAS:            DefaultDAS,
+		ARR:            DefaultARR,
+		SoftDropFactor: DefaultSoftDropFactor,
+		KeepDASCharge:  true,
+	}
+}
+
+type InputManager struct {
+	source   InputSource
+	settings Settings
+	bindings Bindings
+
+	padBindings GamepadBindings
+	gamepads    []ebiten.GamepadID // Connected gamepads with a standard layout
+
+	holdTime map[Action]int
+	softDrop map[Action]float64 // Fraction of a row the soft drop has built up
+}
+
+// NewInputManager reads player input from source, usually NewEbitenSource().
+func NewInputManager(source InputSource, settings Settings, bindings Bindings) *InputManager {
 	return &InputManager{
-		keyHoldTime: make(map[ebiten.Key]int),
+		source:      source,
+		settings:    settings,
+		bindings:    bindings.withDefaults(),
+		padBindings: DefaultGamepadBindings(),
+		holdTime:    make(map[Action]int),
+		softDrop:    make(map[Action]float64),
 	}
 }
 
+// Update refreshes the connected gamepads. Call it once per frame before any input is read.
+func (im *InputManager) Update() {
+	im.updateGamepads()
+}
+
+func (im *InputManager) Settings() Settings {
+	return im.settings
+}
+
+// SetSettings changes how held keys repeat, starting from the next frame.
+func (im *InputManager) SetSettings(settings Settings) {
+	im.settings = settings
+}
+
+// Bindings returns a copy of the current key bindings.
+func (im *InputManager) Bindings() Bindings {
+	return im.bindings.Clone()
+}
+
+// SetBindings replaces the key bindings. Actions left unbound get their default keys.
+func (im *InputManager) SetBindings(bindings Bindings) {
+	im.bindings = bindings.withDefaults()
+	clear(im.holdTime)
+	clear(im.softDrop)
+}
+
+// IsKeyJustPressed reports a raw key press, for text entry and capturing new bindings.
 func (im *InputManager) IsKeyJustPressed(key ebiten.Key) bool {
-	return inpututil.IsKeyJustPressed(key)
+	return im.source.IsKeyJustPressed(key)
 }
 
 func (im *InputManager) IsKeyPressed(key ebiten.Key) bool {
-	return ebiten.IsKeyPressed(key)
+	return im.source.IsKeyPressed(key)
 }
 
 func (im *InputManager) GetJustPressedKeys() []ebiten.Key {
-	return inpututil.AppendJustPressedKeys(nil)
+	return im.source.AppendJustPressedKeys(nil)
 }
 
-func (im *InputManager) ShouldMove(key ebiten.Key) bool {
-	if im.IsKeyJustPressed(key) {
-		im.keyHoldTime[key] = 0
-		return true
+// IsActionJustPressed reports whether any key or gamepad button bound to the action was pressed this frame.
+func (im *InputManager) IsActionJustPressed(action Action) bool {
+	for _, key := range im.bindings[action] {
+		if im.source.IsKeyJustPressed(key) {
+			return true
+		}
 	}
+	return im.isButtonJustPressed(action)
+}
 
-	if im.IsKeyPressed(key) {
-		im.keyHoldTime[key]++
-		if im.keyHoldTime[key] >= InitialDelay {
-			if (im.keyHoldTime[key]-InitialDelay)%RepeatDelay == 0 {
-				return true
-			}
+// IsActionPressed reports whether any key or gamepad button bound to the action is held.
+func (im *InputManager) IsActionPressed(action Action) bool {
+	for _, key := range im.bindings[action] {
+		if im.source.IsKeyPressed(key) {
+			return true
 		}
-	} else {
-		im.keyHoldTime[key] = 0
+	}
+	return im.isButtonPressed(action)
+}
+
+// ShouldRepeat reports whether a held action fires this frame.
+func (im *InputManager) ShouldRepeat(action Action) bool {
+	return im.Repeats(action, 1) > 0
+}
+
+// Repeats returns how many times an action fires this frame: once when it is
+// pressed, then every ARR frames once it has been held for DAS frames. With
+// an ARR of 0 a charged action fires maxRepeats times, which is enough to
+// reach the wall.
+func (im *InputManager) Repeats(action Action, maxRepeats int) int {
+	if im.IsActionJustPressed(action) {
+		im.holdTime[action] = 0
+		return min(1, maxRepeats)
+	}
+
+	if !im.IsActionPressed(action) {
+		im.holdTime[action] = 0
+		return 0
 	}
 
-	return false
+	im.holdTime[action]++
+	held := im.holdTime[action] - im.settings.DAS
+	switch {
+	case held < 0:
+		return 0
+	case im.settings.ARR <= 0:
+		return maxRepeats
+	case held%im.settings.ARR == 0:
+		return min(1, maxRepeats)
+	}
+	return 0
+}
+
+// SoftDrops returns how many rows a held soft drop action moves the piece
+// this frame, SoftDropFactor times faster than gravity that drops the given
+// rows per frame. A fresh press always moves one row.
+func (im *InputManager) SoftDrops(action Action, gravity float64, maxRows int) int {
+	if im.IsActionJustPressed(action) {
+		im.softDrop[action] = 0
+		return min(1, maxRows)
+	}
+
+	if !im.IsActionPressed(action) {
+		im.softDrop[action] = 0
+		return 0
+	}
+
+	im.softDrop[action] += float64(max(im.settings.SoftDropFactor, 1)) * gravity
+	rows := int(im.softDrop[action])
+	im.softDrop[action] -= float64(rows)
+	return min(rows, maxRows)
+}
+
+// ResetCharge drops the DAS charge of every held action, so they have to be
+// held for DAS frames again before repeating. Call it when a piece locks; it
+// does nothing when KeepDASCharge is set.
+func (im *InputManager) ResetCharge() {
+	if im.settings.KeepDASCharge {
+		return
+	}
+	clear(im.holdTime)
 }
@@ -0,0 +1,208 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// holdFor plays the script, where '#' holds the key for a frame and '.'
+// releases it, and returns how many times the key fired on each frame.
+func holdFor(im *InputManager, source *FakeSource, key ebiten.Key, script string, fire func() int) []int {
+	counts := make([]int, len(script))
+	for i, c := range script {
+		if c == '#' {
+			source.Frame(key)
+		} else {
+			source.Frame()
+		}
+		counts[i] = fire()
+	}
+	return counts
+}
+
+func TestRepeats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings Settings
+		script   string
+		expected []int
+	}{
+		{
+			name:     "tap fires once",
+			settings: DefaultSettings(),
+			script:   "#..#",
+			expected: []int{1, 0, 0, 1},
+		},
+		{
+			name:     "repeats every ARR frames after DAS",
+			settings: Settings{DAS: 3, ARR: 2},
+			script:   "########",
+			expected: []int{1, 0, 0, 1, 0, 1, 0, 1},
+		},
+		{
+			name:     "zero ARR jumps to the limit once charged",
+			settings: Settings{DAS: 2, ARR: 0},
+			script:   "#####",
+			expected: []int{1, 0, 10, 10, 10},
+		},
+		{
+			name:     "zero DAS repeats right away",
+			settings: Settings{DAS: 0, ARR: 1},
+			script:   "####",
+			expected: []int{1, 1, 1, 1},
+		},
+		{
+			name:     "release loses the charge",
+			settings: Settings{DAS: 2, ARR: 1},
+			script:   "###.###",
+			expected: []int{1, 0, 1, 0, 1, 0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewFakeSource()
+			im := NewInputManager(source, tt.settings, DefaultBindings())
+			counts := holdFor(im, source, ebiten.KeyLeft, tt.script, func() int {
+				return im.Repeats(ActionMoveLeft, 10)
+			})
+			assert.Equal(t, tt.expected, counts)
+		})
+	}
+}
+
+func TestShouldRepeatMatchesDefaultTiming(t *testing.T) {
+	t.Parallel()
+
+	source := NewFakeSource()
+	im := NewInputManager(source, DefaultSettings(), DefaultBindings())
+
+	var fired []int
+	for frame := range 22 {
+		source.Frame(ebiten.KeyDown)
+		if im.ShouldRepeat(ActionSoftDrop) {
+			fired = append(fired, frame)
+		}
+	}
+	assert.Equal(t, []int{0, 15, 18, 21}, fired)
+}
+
+func TestSoftDrops(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		factor   int
+		gravity  float64
+		script   string
+		expected []int
+	}{
+		{
+			name:     "slower than one row per frame",
+			factor:   10,
+			gravity:  1.0 / 40,
+			script:   "#########",
+			expected: []int{1, 0, 0, 0, 1, 0, 0, 0, 1},
+		},
+		{
+			name:     "several rows per frame at high gravity",
+			factor:   20,
+			gravity:  1.0 / 5,
+			script:   "###",
+			expected: []int{1, 4, 4},
+		},
+		{
+			name:     "capped at the board height",
+			factor:   100,
+			gravity:  1,
+			script:   "##",
+			expected: []int{1, 20},
+		},
+		{
+			name:     "release resets the progress",
+			factor:   10,
+			gravity:  1.0 / 40,
+			script:   "###.####",
+			expected: []int{1, 0, 0, 0, 1, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewFakeSource()
+			im := NewInputManager(source, Settings{SoftDropFactor: tt.factor}, DefaultBindings())
+			counts := holdFor(im, source, ebiten.KeyDown, tt.script, func() int {
+				return im.SoftDrops(ActionSoftDrop, tt.gravity, 20)
+			})
+			assert.Equal(t, tt.expected, counts)
+		})
+	}
+}
+
+func TestResetCharge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		keep     bool
+		expected []int
+	}{
+		{name: "charge carries over to the next piece", keep: true, expected: []int{1, 0, 1, 1, 1, 1}},
+		{name: "charge is cut when a piece locks", keep: false, expected: []int{1, 0, 1, 0, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewFakeSource()
+			im := NewInputManager(source, Settings{DAS: 2, ARR: 1, KeepDASCharge: tt.keep}, DefaultBindings())
+
+			counts := make([]int, 0, len(tt.expected))
+			for frame := range len(tt.expected) {
+				source.Frame(ebiten.KeyRight)
+				counts = append(counts, im.Repeats(ActionMoveRight, 1))
+				if frame == 2 {
+					im.ResetCharge()
+				}
+			}
+			assert.Equal(t, tt.expected, counts)
+		})
+	}
+}
+
+func TestActionsFollowBindings(t *testing.T) {
+	t.Parallel()
+
+	source := NewFakeSource()
+	im := NewInputManager(source, DefaultSettings(), Bindings{ActionHardDrop: {ebiten.KeyW, ebiten.KeyEnter}})
+
+	source.Frame(ebiten.KeyEnter)
+	assert.True(t, im.IsActionJustPressed(ActionHardDrop))
+	assert.True(t, im.IsActionJustPressed(ActionConfirm), "one key can serve several actions")
+
+	source.Frame(ebiten.KeyEnter, ebiten.KeyW)
+	assert.True(t, im.IsActionJustPressed(ActionHardDrop))
+	assert.True(t, im.IsActionPressed(ActionHardDrop))
+
+	source.Frame(ebiten.KeySpace)
+	assert.False(t, im.IsActionPressed(ActionHardDrop), "rebinding replaces the default keys")
+	assert.False(t, im.IsActionJustPressed(ActionHardDrop))
+
+	source.Frame(ebiten.KeyLeft)
+	assert.True(t, im.IsActionJustPressed(ActionMoveLeft), "unbound actions keep their default keys")
+
+	im.SetBindings(Bindings{ActionMoveLeft: {ebiten.KeyA}})
+	source.Frame(ebiten.KeyA)
+	assert.True(t, im.IsActionJustPressed(ActionMoveLeft))
+	assert.False(t, im.IsActionJustPressed(ActionHardDrop))
+	assert.Equal(t, []ebiten.Key{ebiten.KeySpace}, im.Bindings()[ActionHardDrop])
+}
@@ -0,0 +1,67 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// KeySource reports the keyboard state for the current frame.
+type KeySource interface {
+	IsKeyPressed(key ebiten.Key) bool
+	IsKeyJustPressed(key ebiten.Key) bool
+	AppendJustPressedKeys(keys []ebiten.Key) []ebiten.Key
+}
+
+// InputSource is everything the InputManager reads from the outside world.
+// The game reads ebiten through NewEbitenSource; tests script a FakeSource.
+type InputSource interface {
+	KeySource
+	GamepadSource
+}
+
+// EbitenSource reads the keyboard and gamepads through ebiten.
+type EbitenSource struct{}
+
+func NewEbitenSource() EbitenSource {
+	return EbitenSource{}
+}
+
+func (EbitenSource) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+func (EbitenSource) IsKeyJustPressed(key ebiten.Key) bool {
+	return inpututil.IsKeyJustPressed(key)
+}
+
+func (EbitenSource) AppendJustPressedKeys(keys []ebiten.Key) []ebiten.Key {
+	return inpututil.AppendJustPressedKeys(keys)
+}
+
+func (EbitenSource) AppendGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID {
+	return ebiten.AppendGamepadIDs(ids)
+}
+
+func (EbitenSource) AppendJustConnectedGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID {
+	return inpututil.AppendJustConnectedGamepadIDs(ids)
+}
+
+func (EbitenSource) IsGamepadJustDisconnected(id ebiten.GamepadID) bool {
+	return inpututil.IsGamepadJustDisconnected(id)
+}
+
+func (EbitenSource) IsStandardGamepadLayoutAvailable(id ebiten.GamepadID) bool {
+	return ebiten.IsStandardGamepadLayoutAvailable(id)
+}
+
+func (EbitenSource) IsStandardGamepadButtonPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	return ebiten.IsStandardGamepadButtonPressed(id, button)
+}
+
+func (EbitenSource) IsStandardGamepadButtonJustPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	return inpututil.IsStandardGamepadButtonJustPressed(id, button)
+}
+
+func (EbitenSource) GamepadName(id ebiten.GamepadID) string {
+	return ebiten.GamepadName(id)
+}
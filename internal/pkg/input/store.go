@@ -0,0 +1,91 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/piotrowski/ebitris/internal/pkg/datadir"
+)
+
+const defaultBindingsFile = "controls.json"
+
+// bindingsVersion is bumped whenever the layout of the controls file changes.
+const bindingsVersion = 1
+
+type BindingsLoader interface {
+	LoadBindings() Bindings
+}
+
+type BindingsSaver interface {
+	SaveBindings(bindings Bindings)
+}
+
+type bindingsFile struct {
+	Version  int      `json:"version"`
+	Bindings Bindings `json:"bindings"`
+}
+
+// BindingsStore keeps the player's key bindings in a JSON file next to the scores.
+type BindingsStore struct {
+	filePath string
+}
+
+func NewBindingsStore() *BindingsStore {
+	return newBindingsStoreAt(datadir.Path(defaultBindingsFile))
+}
+
+func newBindingsStoreAt(filePath string) *BindingsStore {
+	return &BindingsStore{filePath: filePath}
+}
+
+// LoadBindings returns the saved bindings, or the defaults when there are none
+// or they cannot be read. Actions missing from the file keep their default keys.
+func (s *BindingsStore) LoadBindings() Bindings {
+	bindings, err := s.load()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("failed to load controls, using defaults", "subsystem", "input", "err", err)
+		}
+		return DefaultBindings()
+	}
+	return bindings.withDefaults()
+}
+
+func (s *BindingsStore) load() (Bindings, error) {
+	jsonData, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var file bindingsFile
+	if err := json.Unmarshal(jsonData, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse controls %s: %w", s.filePath, err)
+	}
+	if file.Version != bindingsVersion {
+		return nil, fmt.Errorf("unsupported controls version %d in %s", file.Version, s.filePath)
+	}
+	return file.Bindings, nil
+}
+
+func (s *BindingsStore) SaveBindings(bindings Bindings) {
+	slog.Info("saving controls", "subsystem", "input")
+
+	if err := s.save(bindings); err != nil {
+		slog.Error("failed to save controls", "subsystem", "input", "err", err)
+	}
+}
+
+func (s *BindingsStore) save(bindings Bindings) error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0o755); err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(bindingsFile{Version: bindingsVersion, Bindings: bindings}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filePath, jsonData, 0o600)
+}
@@ -0,0 +1,92 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoadBindings(t *testing.T) {
+	t.Parallel()
+
+	store := newBindingsStoreAt(filepath.Join(t.TempDir(), "controls.json"))
+	assert.Equal(t, DefaultBindings(), store.LoadBindings())
+
+	bindings := DefaultBindings()
+	bindings[ActionMoveLeft] = []ebiten.Key{ebiten.KeyA, ebiten.KeyJ}
+	bindings[ActionHold] = []ebiten.Key{ebiten.KeyTab}
+	store.SaveBindings(bindings)
+
+	assert.Equal(t, bindings, store.LoadBindings())
+}
+
+func TestLoadBindingsFallsBackToDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		contents string
+		expected Bindings
+	}{
+		{
+			name:     "missing actions keep their defaults",
+			contents: `{"version": 1, "bindings": {"HardDrop": ["W"]}}`,
+			expected: func() Bindings {
+				b := DefaultBindings()
+				b[ActionHardDrop] = []ebiten.Key{ebiten.KeyW}
+				return b
+			}(),
+		},
+		{
+			name:     "unknown action",
+			contents: `{"version": 1, "bindings": {"Teleport": ["T"]}}`,
+			expected: DefaultBindings(),
+		},
+		{
+			name:     "unknown key",
+			contents: `{"version": 1, "bindings": {"HardDrop": ["NoSuchKey"]}}`,
+			expected: DefaultBindings(),
+		},
+		{
+			name:     "unsupported version",
+			contents: `{"version": 99, "bindings": {"HardDrop": ["W"]}}`,
+			expected: DefaultBindings(),
+		},
+		{
+			name:     "not JSON",
+			contents: `controls`,
+			expected: DefaultBindings(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "controls.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+
+			assert.Equal(t, tt.expected, newBindingsStoreAt(path).LoadBindings())
+		})
+	}
+}
+
+func TestActionNames(t *testing.T) {
+	t.Parallel()
+
+	for _, action := range Actions() {
+		text, err := action.MarshalText()
+		require.NoError(t, err)
+
+		var parsed Action
+		require.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, action, parsed)
+	}
+
+	_, err := actionCount.MarshalText()
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/piotrowski/ebitris/internal/sim"
+	"github.com/piotrowski/ebitris/internal/tetris"
+)
+
+// Version is bumped whenever the replay format or the game rules change in a
+// way that would make older replays play out differently.
+const Version = 7
+
+// Input is one action taken by the player on a given frame.
+type Input struct {
+	Frame  int           `json:"frame"`
+	Action tetris.Action `json:"action"`
+}
+
+// Replay holds everything needed to reproduce a game: the options it was
+// started with and every input the player made.
+type Replay struct {
+	Version        int                   `json:"version"`
+	Date           time.Time             `json:"date"`
+	Mode           sim.Mode              `json:"mode"`
+	Seed           uint64                `json:"seed"`
+	Width          int                   `json:"width"`
+	Height         int                   `json:"height"`
+	RotationSystem string                `json:"rotationSystem"`
+	Randomizer     tetris.RandomizerKind `json:"randomizer"`
+	Gravity        tetris.GravityCurve   `json:"gravity"`
+	LockDelay      int                   `json:"lockDelay"`
+	LockReset      tetris.LockResetMode  `json:"lockReset"`
+	MaxLockResets  int                   `json:"maxLockResets"`
+	EntryDelay     int                   `json:"entryDelay"`
+	LineClearDelay int                   `json:"lineClearDelay"`
+	PreviewCount   int                   `json:"previewCount"`
+	Frames         int                   `json:"frames"`
+	Inputs         []Input               `json:"inputs"`
+}
+
+// Options returns the game options the replay was recorded with.
+func (r Replay) Options() (tetris.Options, error) {
+	if r.Version != Version {
+		return tetris.Options{}, fmt.Errorf("unsupported replay version %d, expected %d", r.Version, Version)
+	}
+
+	rotationSystem, ok := tetris.RotationSystemByName(r.RotationSystem)
+	if !ok {
+		return tetris.Options{}, fmt.Errorf("unknown rotation system %q", r.RotationSystem)
+	}
+
+	return tetris.Options{
+		Width:          r.Width,
+		Height:         r.Height,
+		Seed:           r.Seed,
+		RotationSystem: rotationSystem,
+		Randomizer:     r.Randomizer,
+		Gravity:        r.Gravity,
+		LockDelay:      tetris.Frames(r.LockDelay),
+		LockReset:      r.LockReset,
+		MaxLockResets:  r.MaxLockResets,
+		EntryDelay:     r.EntryDelay,
+		LineClearDelay: r.LineClearDelay,
+		PreviewCount:   r.PreviewCount,
+	}, nil
+}
+
+// Recorder collects the inputs of a live game frame by frame.
+type Recorder struct {
+	replay Replay
+}
+
+func NewRecorder(mode sim.Mode, opts tetris.Options) *Recorder {
+	rotationName := tetris.NewSRS().Name()
+	if opts.RotationSystem != nil {
+		rotationName = opts.RotationSystem.Name()
+	}
+
+	lockDelay := tetris.DefaultLockDelay
+	if opts.LockDelay != nil {
+		lockDelay = *opts.LockDelay
+	}
+
+	return &Recorder{
+		replay: Replay{
+			Version:        Version,
+			Date:           time.Now(),
+			Mode:           mode,
+			Seed:           opts.Seed,
+			Width:          opts.Width,
+			Height:         opts.Height,
+			RotationSystem: rotationName,
+			Randomizer:     opts.Randomizer,
+			Gravity:        opts.Gravity,
+			LockDelay:      lockDelay,
+			LockReset:      opts.LockReset,
+			MaxLockResets:  opts.MaxLockResets,
+			EntryDelay:     opts.EntryDelay,
+			LineClearDelay: opts.LineClearDelay,
+			PreviewCount:   opts.PreviewCount,
+		},
+	}
+}
+
+// Record stores the actions taken during the current frame and moves on to the next one.
+func (r *Recorder) Record(actions []tetris.Action) {
+	for _, action := range actions {
+		r.replay.Inputs = append(r.replay.Inputs, Input{Frame: r.replay.Frames, Action: action})
+	}
+	r.replay.Frames++
+}
+
+// Replay returns a copy of everything recorded so far.
+func (r *Recorder) Replay() Replay {
+	replay := r.replay
+	replay.Inputs = append([]Input(nil), r.replay.Inputs...)
+	return replay
+}
+
+// Player re-runs a recorded game one frame at a time.
+type Player struct {
+	replay Replay
+	sim    *sim.Simulation
+
+	frame     int
+	nextInput int
+}
+
+func NewPlayer(replay Replay) (*Player, error) {
+	opts, err := replay.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Player{
+		replay: replay,
+		sim:    sim.New(replay.Mode, opts),
+	}, nil
+}
+
+// Step plays the next recorded frame and reports what happened during it.
+func (p *Player) Step() sim.Result {
+	if p.Done() {
+		return sim.Result{Tick: p.sim.Tick()}
+	}
+
+	var actions []tetris.Action
+	for p.nextInput < len(p.replay.Inputs) && p.replay.Inputs[p.nextInput].Frame == p.frame {
+		actions = append(actions, p.replay.Inputs[p.nextInput].Action)
+		p.nextInput++
+	}
+
+	p.frame++
+	return p.sim.Step(actions)
+}
+
+// Done reports whether every recorded frame has been played.
+func (p *Player) Done() bool {
+	return p.frame >= p.replay.Frames
+}
+
+func (p *Player) State() *tetris.GameState {
+	return p.sim.State()
+}
+
+func (p *Player) Frame() int {
+	return p.frame
+}
+
+func (p *Player) TotalFrames() int {
+	return p.replay.Frames
+}
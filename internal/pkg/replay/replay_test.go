@@ -0,0 +1,150 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrowski/ebitris/internal/sim"
+	"github.com/piotrowski/ebitris/internal/tetris"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scriptActions = map[rune]tetris.Action{
+	'L': tetris.ActionMoveLeft,
+	'R': tetris.ActionMoveRight,
+	'U': tetris.ActionRotate,
+	'Z': tetris.ActionRotateCCW,
+	'D': tetris.ActionMoveDown,
+	'H': tetris.ActionHardDrop,
+}
+
+// recordGame plays one action per frame from script, with '.' as an idle frame.
+func recordGame(opts tetris.Options, script string) (*tetris.GameState, Replay) {
+	gs := tetris.NewGameState(opts)
+	recorder := NewRecorder(sim.ModeMarathon, gs.GetOptions())
+
+	for _, input := range script {
+		var actions []tetris.Action
+		if action, ok := scriptActions[input]; ok {
+			actions = append(actions, action)
+		}
+		recorder.Record(actions)
+		gs.Step(actions)
+	}
+	return gs, recorder.Replay()
+}
+
+func assertSameGame(t *testing.T, expected, actual *tetris.GameState) {
+	t.Helper()
+
+	expectedBoard, actualBoard := expected.GetBoard(), actual.GetBoard()
+	for y := 0; y < expectedBoard.Height; y++ {
+		for x := 0; x < expectedBoard.Width; x++ {
+			assert.Equal(t, expectedBoard.Cell(x, y), actualBoard.Cell(x, y), "cell %d,%d", x, y)
+		}
+	}
+	assert.Equal(t, expected.GetScore(), actual.GetScore())
+	assert.Equal(t, expected.GetLinesCleared(), actual.GetLinesCleared())
+	assert.Equal(t, expected.GetCurrentPiece(), actual.GetCurrentPiece())
+	assert.Equal(t, expected.IsGameOver(), actual.IsGameOver())
+}
+
+func TestPlaybackMatchesOriginalGame(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		opts   tetris.Options
+		script string
+	}{
+		{
+			name:   "SRS with 7-bag",
+			opts:   tetris.Options{Width: 10, Height: 20, Seed: 7, RotationSystem: tetris.NewSRS(), Randomizer: tetris.RandomizerSevenBag},
+			script: "LLLH....RRRRH.UH..ZLLH.URRRRRH...DDH.ULLLLLH.URRH",
+		},
+		{
+			name:   "ARS with history randomizer and idle gravity frames",
+			opts:   tetris.Options{Width: 10, Height: 20, Seed: 42, RotationSystem: tetris.NewARS(), Randomizer: tetris.RandomizerHistory, LockReset: tetris.LockResetStep},
+			script: "LL" + strings.Repeat(".", 60) + "UUR.....H",
+		},
+		{
+			name:   "entry and line clear delays",
+			opts:   tetris.Options{Width: 4, Height: 8, Seed: 11, EntryDelay: 3, LineClearDelay: 10},
+			script: "H...H..H" + strings.Repeat(".", 20) + "LH.RH" + strings.Repeat(".", 15) + "H",
+		},
+		{
+			name:   "shorter preview queue",
+			opts:   tetris.Options{Width: 10, Height: 20, Seed: 9, PreviewCount: 2},
+			script: "LHRRUH",
+		},
+		{
+			name:   "20G with pieces sliding along the stack",
+			opts:   tetris.Options{Width: 10, Height: 20, Seed: 5, Gravity: tetris.Gravity20G},
+			script: "LLL" + strings.Repeat(".", 30) + "RRRRU" + strings.Repeat(".", 30) + "LLUUH",
+		},
+		{
+			name:   "game played until top out",
+			opts:   tetris.Options{Width: 6, Height: 8, Seed: 3, RotationSystem: tetris.NewNES(), Randomizer: tetris.RandomizerPure},
+			script: "HHHHHHHHHHHHHHHHHHHH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			original, replay := recordGame(tt.opts, tt.script)
+
+			player, err := NewPlayer(replay)
+			require.NoError(t, err)
+			for !player.Done() {
+				player.Step()
+			}
+
+			assert.Equal(t, len(tt.script), player.Frame())
+			assertSameGame(t, original, player.State())
+			assert.Equal(t, original.GetOptions().PreviewCount, player.State().GetOptions().PreviewCount)
+		})
+	}
+}
+
+func TestSaveAndLoadLatest(t *testing.T) {
+	t.Parallel()
+
+	store := newStoreAt(t.TempDir())
+
+	_, err := store.LoadLatest()
+	assert.ErrorIs(t, err, ErrNoReplays)
+
+	opts := tetris.Options{Width: 10, Height: 20, Seed: 11, RotationSystem: tetris.NewSRS()}
+	_, older := recordGame(opts, "LH")
+	older.Date = time.Now().Add(-time.Hour)
+	original, newer := recordGame(opts, "RRUH..ZH")
+
+	store.SaveReplay(older)
+	store.SaveReplay(newer)
+
+	loaded, err := store.LoadLatest()
+	require.NoError(t, err)
+	assert.Equal(t, newer.Inputs, loaded.Inputs)
+	assert.Equal(t, newer.Frames, loaded.Frames)
+
+	player, err := NewPlayer(loaded)
+	require.NoError(t, err)
+	for !player.Done() {
+		player.Step()
+	}
+	assertSameGame(t, original, player.State())
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	_, replay := recordGame(tetris.Options{Seed: 1}, "H")
+	replay.Version = Version + 1
+
+	_, err := NewPlayer(replay)
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/piotrowski/ebitris/internal/pkg/datadir"
+)
+
+const defaultReplayDir = "replays"
+
+var ErrNoReplays = errors.New("no replays saved")
+
+type Saver interface {
+	SaveReplay(replay Replay)
+}
+
+type Loader interface {
+	LoadLatest() (Replay, error)
+}
+
+// Store keeps replays as one JSON file per game.
+type Store struct {
+	dir string
+}
+
+func NewStore() *Store {
+	return newStoreAt(datadir.Path(defaultReplayDir))
+}
+
+func newStoreAt(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) SaveReplay(replay Replay) {
+	slog.Info("saving replay", "subsystem", "replay", "frames", replay.Frames, "inputs", len(replay.Inputs))
+
+	if err := s.save(replay); err != nil {
+		slog.Error("failed to save replay", "subsystem", "replay", "err", err)
+	}
+}
+
+func (s *Store) save(replay Replay) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+
+	name := replay.Date.UTC().Format("20060102-150405.000") + ".json"
+	return os.WriteFile(filepath.Join(s.dir, name), jsonData, 0o600)
+}
+
+// LoadLatest returns the most recently saved replay.
+func (s *Store) LoadLatest() (Replay, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return Replay{}, err
+	}
+	if len(files) == 0 {
+		return Replay{}, ErrNoReplays
+	}
+
+	// File names are timestamps, so the last one in order is the newest
+	slices.Sort(files)
+	return load(files[len(files)-1])
+}
+
+func load(path string) (Replay, error) {
+	jsonData, err := os.ReadFile(path)
+	if err != nil {
+		return Replay{}, err
+	}
+
+	var replay Replay
+	if err := json.Unmarshal(jsonData, &replay); err != nil {
+		return Replay{}, fmt.Errorf("failed to parse replay %s: %w", path, err)
+	}
+	if replay.Version != Version {
+		return Replay{}, fmt.Errorf("unsupported replay version %d in %s", replay.Version, path)
+	}
+	return replay, nil
+}
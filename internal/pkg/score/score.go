@@ -2,14 +2,20 @@ package score
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"os"
-	"path"
+	"path/filepath"
 	"slices"
 	"time"
+
+	"github.com/piotrowski/ebitris/internal/pkg/datadir"
 )
 
-const defaultSaveFile = ".ebitris/scores.json"
+const (
+	defaultSaveFile      = "scores.json"
+	defaultUltraSaveFile = "ultra.json"
+)
 
 type Getter interface {
 	GetPage(page, size int) ([]ScoreEntry, bool)
@@ -33,7 +39,16 @@ type ScoreManager struct {
 }
 
 func NewScoreManager() *ScoreManager {
-	return newScoreManagerAt(defaultSaveFile)
+	manager := newScoreManagerAt(datadir.Path(defaultSaveFile))
+	manager.migrateFrom(datadir.LegacyPath(defaultSaveFile))
+	return manager
+}
+
+// NewUltraScoreManager keeps the Ultra leaderboard, apart from the Marathon one.
+func NewUltraScoreManager() *ScoreManager {
+	manager := newScoreManagerAt(datadir.Path(defaultUltraSaveFile))
+	manager.migrateFrom(datadir.LegacyPath(defaultUltraSaveFile))
+	return manager
 }
 
 func newScoreManagerAt(filePath string) *ScoreManager {
@@ -53,6 +68,29 @@ func newScoreManagerAt(filePath string) *ScoreManager {
 	return manager
 }
 
+// migrateFrom takes over the scores saved at legacyPath by versions that kept
+// them in the working directory, unless scores were saved at the new path already.
+func (sm *ScoreManager) migrateFrom(legacyPath string) {
+	if _, err := os.Stat(sm.filePath); !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+
+	legacy := &ScoreManager{filePath: legacyPath}
+	if err := legacy.loadScores(); err != nil {
+		slog.Warn("failed to load scores to migrate", "subsystem", "score", "path", legacyPath, "err", err)
+		return
+	}
+	if len(legacy.scores) == 0 {
+		return
+	}
+
+	slog.Info("migrating scores", "subsystem", "score", "from", legacyPath, "to", sm.filePath, "scores", len(legacy.scores))
+	sm.scores = legacy.scores
+	if err := sm.saveScore(); err != nil {
+		slog.Error("failed to save migrated scores", "subsystem", "score", "err", err)
+	}
+}
+
 func (sm *ScoreManager) SaveScore(initials string, score, level, lines int) {
 	slog.Info("saving score", "subsystem", "score", "initials", initials, "score", score, "level", level, "lines", lines)
 	entry := ScoreEntry{
@@ -124,7 +162,7 @@ func (sm *ScoreManager) loadScores() error {
 }
 
 func ensureBaseDir(fpath string) error {
-	baseDir := path.Dir(fpath)
+	baseDir := filepath.Dir(fpath)
 	info, err := os.Stat(baseDir)
 	if err == nil && info.IsDir() {
 		return nil
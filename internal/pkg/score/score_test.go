@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetPage(t *testing.T) {
@@ -118,3 +119,41 @@ func TestSaveAndLoadScores(t *testing.T) {
 	assert.Equal(t, 5, entries[0].Level)
 	assert.Equal(t, 42, entries[0].Lines)
 }
+
+func TestMigrateScores(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	legacyPath := filepath.Join(dir, "legacy", "scores.json")
+	newScoreManagerAt(legacyPath).SaveScore("OLD", 1200, 3, 25)
+
+	path := filepath.Join(dir, "home", "scores.json")
+	sm := newScoreManagerAt(path)
+	sm.migrateFrom(legacyPath)
+	entries, _ := sm.GetPage(0, 10)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "OLD", entries[0].Initials)
+
+	entries, _ = newScoreManagerAt(path).GetPage(0, 10)
+	require.Len(t, entries, 1, "the migrated scores are saved at the new path")
+
+	sm.SaveScore("NEW", 500, 1, 4)
+	newScoreManagerAt(legacyPath).SaveScore("OLD", 900, 2, 12)
+	again := newScoreManagerAt(path)
+	again.migrateFrom(legacyPath)
+	entries, _ = again.GetPage(0, 10)
+	assert.Len(t, entries, 2, "migration happens only while the new path has no scores")
+}
+
+func TestMigrateWithoutLegacyScores(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scores.json")
+	sm := newScoreManagerAt(path)
+	sm.migrateFrom(filepath.Join(dir, "missing", "scores.json"))
+
+	entries, _ := sm.GetPage(0, 10)
+	assert.Empty(t, entries)
+	assert.NoFileExists(t, path)
+}
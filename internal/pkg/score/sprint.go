@@ -0,0 +1,126 @@
+package score
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/piotrowski/ebitris/internal/pkg/datadir"
+)
+
+const defaultSprintSaveFile = "sprint.json"
+
+type SprintGetter interface {
+	GetSprintPage(page, size int) ([]SprintEntry, bool)
+	// PersonalBest returns the fastest sprint saved so far.
+	PersonalBest() (SprintEntry, bool)
+}
+
+type SprintSaver interface {
+	SaveSprint(entry SprintEntry)
+}
+
+// SprintEntry is one finished 40 line sprint. Times are counted in game
+// ticks so they are exact to the frame.
+type SprintEntry struct {
+	Initials  string
+	Ticks     int   // Time to clear every line
+	LineTicks []int // Time each line was cleared at, for pacing later runs against this one
+	Pieces    int
+	Date      time.Time
+}
+
+// SprintManager keeps the sprint leaderboard, fastest first.
+type SprintManager struct {
+	sprints  []SprintEntry
+	filePath string
+}
+
+func NewSprintManager() *SprintManager {
+	return newSprintManagerAt(datadir.Path(defaultSprintSaveFile))
+}
+
+func newSprintManagerAt(filePath string) *SprintManager {
+	manager := &SprintManager{
+		sprints:  []SprintEntry{},
+		filePath: filePath,
+	}
+
+	if err := ensureBaseDir(filePath); err != nil {
+		panic(err)
+	}
+
+	if err := manager.load(); err != nil {
+		panic(err)
+	}
+
+	return manager
+}
+
+func (sm *SprintManager) SaveSprint(entry SprintEntry) {
+	slog.Info("saving sprint", "subsystem", "score", "initials", entry.Initials, "ticks", entry.Ticks)
+
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	sm.sprints = append(sm.sprints, entry)
+	sm.sort()
+
+	if err := sm.save(); err != nil {
+		slog.Error("failed to save sprint", "subsystem", "score", "err", err)
+	}
+}
+
+func (sm *SprintManager) GetSprintPage(page, size int) ([]SprintEntry, bool) {
+	start := page * size
+	if start >= len(sm.sprints) {
+		return []SprintEntry{}, false
+	}
+
+	end := min(start+size, len(sm.sprints))
+	return sm.sprints[start:end], end < len(sm.sprints)
+}
+
+func (sm *SprintManager) PersonalBest() (SprintEntry, bool) {
+	if len(sm.sprints) == 0 {
+		return SprintEntry{}, false
+	}
+	return sm.sprints[0], true
+}
+
+// sort orders the sprints by time, the earlier run first on a tie.
+func (sm *SprintManager) sort() {
+	slices.SortFunc(sm.sprints, func(a, b SprintEntry) int {
+		if a.Ticks != b.Ticks {
+			return a.Ticks - b.Ticks
+		}
+		return a.Date.Compare(b.Date)
+	})
+}
+
+func (sm *SprintManager) save() error {
+	jsonData, err := json.Marshal(sm.sprints)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(sm.filePath, jsonData, 0o600)
+}
+
+func (sm *SprintManager) load() error {
+	jsonData, err := os.ReadFile(sm.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := json.Unmarshal(jsonData, &sm.sprints); err != nil {
+		return err
+	}
+	sm.sort()
+	return nil
+}
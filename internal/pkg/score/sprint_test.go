@@ -0,0 +1,67 @@
+package score
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprintLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	sm := newSprintManagerAt(filepath.Join(t.TempDir(), "sprint.json"))
+
+	_, ok := sm.PersonalBest()
+	assert.False(t, ok)
+
+	sm.SaveSprint(SprintEntry{Initials: "SLO", Ticks: 4000, Date: now})
+	sm.SaveSprint(SprintEntry{Initials: "NEW", Ticks: 3000, Date: now})
+	sm.SaveSprint(SprintEntry{Initials: "OLD", Ticks: 3000, Date: now.Add(-time.Hour)})
+	sm.SaveSprint(SprintEntry{Initials: "MID", Ticks: 3500, Date: now})
+
+	page, hasMore := sm.GetSprintPage(0, 3)
+	assert.Equal(t, []string{"OLD", "NEW", "MID"}, sprintInitials(page), "fastest first, ties to the earlier run")
+	assert.True(t, hasMore)
+
+	page, hasMore = sm.GetSprintPage(1, 3)
+	assert.Equal(t, []string{"SLO"}, sprintInitials(page))
+	assert.False(t, hasMore)
+
+	page, hasMore = sm.GetSprintPage(2, 3)
+	assert.Empty(t, page)
+	assert.False(t, hasMore)
+
+	best, ok := sm.PersonalBest()
+	require.True(t, ok)
+	assert.Equal(t, "OLD", best.Initials)
+}
+
+func TestSaveAndLoadSprints(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sprint.json")
+	sm := newSprintManagerAt(path)
+	sm.SaveSprint(SprintEntry{Initials: "B", Ticks: 2000, LineTicks: []int{10, 20}, Pieces: 100})
+	sm.SaveSprint(SprintEntry{Initials: "A", Ticks: 1900})
+
+	loaded := newSprintManagerAt(path)
+	entries, _ := loaded.GetSprintPage(0, 10)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "A", entries[0].Initials)
+	assert.Equal(t, "B", entries[1].Initials)
+	assert.Equal(t, []int{10, 20}, entries[1].LineTicks)
+	assert.Equal(t, 100, entries[1].Pieces)
+	assert.False(t, entries[1].Date.IsZero())
+}
+
+func sprintInitials(entries []SprintEntry) []string {
+	initials := make([]string, len(entries))
+	for i, e := range entries {
+		initials[i] = e.Initials
+	}
+	return initials
+}
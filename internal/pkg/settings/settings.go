@@ -0,0 +1,93 @@
+package settings
+
+// Limits for the values players can pick. Boards wider or taller than this
+// no longer fit next to the HUD in the 600x800 layout.
+const (
+	MinBoardWidth  = 6
+	MaxBoardWidth  = 11
+	MinBoardHeight = 16
+	MaxBoardHeight = 21
+
+	MinPreviewCount = 1
+	MaxPreviewCount = 6 // As many as fit under "Next:", see tetris.MaxPreviewCount
+
+	MinWindowScale = 50
+	MaxWindowScale = 200
+)
+
+// Settings is everything the player can change from the options scene.
+type Settings struct {
+	Audio    Audio    `json:"audio"`
+	Controls Controls `json:"controls"`
+	Gameplay Gameplay `json:"gameplay"`
+	Display  Display  `json:"display"`
+}
+
+// Audio volumes go from 0 for silence to 1 for full volume. Music and
+// effects play at their own volume times the master volume.
+type Audio struct {
+	MasterVolume  float64 `json:"masterVolume"`
+	MusicVolume   float64 `json:"musicVolume"`
+	EffectsVolume float64 `json:"effectsVolume"`
+	Muted         bool    `json:"muted"`
+}
+
+// Controls tunes how held keys repeat, see input.Settings. Key bindings are
+// saved separately by the controls scene.
+type Controls struct {
+	DAS            int  `json:"das"`
+	ARR            int  `json:"arr"`
+	SoftDropFactor int  `json:"softDropFactor"`
+	KeepDASCharge  bool `json:"keepDasCharge"`
+}
+
+// Gameplay options take effect from the next game.
+type Gameplay struct {
+	BoardWidth   int `json:"boardWidth"`
+	BoardHeight  int `json:"boardHeight"`
+	PreviewCount int `json:"previewCount"` // Upcoming pieces shown
+}
+
+type Display struct {
+	WindowScale int  `json:"windowScale"` // Window size in percent of the 600x800 layout
+	Fullscreen  bool `json:"fullscreen"`
+}
+
+func Default() Settings {
+	return Settings{
+		Audio: Audio{
+			MasterVolume:  1,
+			MusicVolume:   0.1,
+			EffectsVolume: 1,
+		},
+		Controls: Controls{
+			DAS:            15,
+			ARR:            3,
+			SoftDropFactor: 20,
+			KeepDASCharge:  true,
+		},
+		Gameplay: Gameplay{
+			BoardWidth:   10,
+			BoardHeight:  20,
+			PreviewCount: 5,
+		},
+		Display: Display{
+			WindowScale: 100,
+		},
+	}
+}
+
+// Clamped returns the settings with every value moved into its allowed range.
+func (s Settings) Clamped() Settings {
+	s.Audio.MasterVolume = min(max(s.Audio.MasterVolume, 0), 1)
+	s.Audio.MusicVolume = min(max(s.Audio.MusicVolume, 0), 1)
+	s.Audio.EffectsVolume = min(max(s.Audio.EffectsVolume, 0), 1)
+	s.Controls.DAS = max(s.Controls.DAS, 0)
+	s.Controls.ARR = max(s.Controls.ARR, 0)
+	s.Controls.SoftDropFactor = max(s.Controls.SoftDropFactor, 1)
+	s.Gameplay.BoardWidth = min(max(s.Gameplay.BoardWidth, MinBoardWidth), MaxBoardWidth)
+	s.Gameplay.BoardHeight = min(max(s.Gameplay.BoardHeight, MinBoardHeight), MaxBoardHeight)
+	s.Gameplay.PreviewCount = min(max(s.Gameplay.PreviewCount, MinPreviewCount), MaxPreviewCount)
+	s.Display.WindowScale = min(max(s.Display.WindowScale, MinWindowScale), MaxWindowScale)
+	return s
+}
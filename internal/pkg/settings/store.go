@@ -0,0 +1,91 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/piotrowski/ebitris/internal/pkg/datadir"
+)
+
+const defaultSettingsFile = "settings.json"
+
+// settingsVersion is bumped whenever the layout of the settings file changes.
+const settingsVersion = 1
+
+type Loader interface {
+	LoadSettings() Settings
+}
+
+type Saver interface {
+	SaveSettings(settings Settings)
+}
+
+type settingsFile struct {
+	Version int `json:"version"`
+	Settings
+}
+
+// Store keeps the settings in a JSON file in the game's data directory.
+type Store struct {
+	filePath string
+}
+
+func NewStore() *Store {
+	return newStoreAt(datadir.Path(defaultSettingsFile))
+}
+
+func newStoreAt(filePath string) *Store {
+	return &Store{filePath: filePath}
+}
+
+// LoadSettings returns the saved settings, or the defaults when there are none
+// or they cannot be read. Options missing from the file keep their defaults.
+func (s *Store) LoadSettings() Settings {
+	settings, err := s.load()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("failed to load settings, using defaults", "subsystem", "settings", "err", err)
+		}
+		return Default()
+	}
+	return settings.Clamped()
+}
+
+func (s *Store) load() (Settings, error) {
+	jsonData, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	file := settingsFile{Settings: Default()}
+	if err := json.Unmarshal(jsonData, &file); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse settings %s: %w", s.filePath, err)
+	}
+	if file.Version != settingsVersion {
+		return Settings{}, fmt.Errorf("unsupported settings version %d in %s", file.Version, s.filePath)
+	}
+	return file.Settings, nil
+}
+
+func (s *Store) SaveSettings(settings Settings) {
+	slog.Info("saving settings", "subsystem", "settings")
+
+	if err := s.save(settings); err != nil {
+		slog.Error("failed to save settings", "subsystem", "settings", "err", err)
+	}
+}
+
+func (s *Store) save(settings Settings) error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0o755); err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(settingsFile{Version: settingsVersion, Settings: settings}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filePath, jsonData, 0o600)
+}
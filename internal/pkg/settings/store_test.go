@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoadSettings(t *testing.T) {
+	t.Parallel()
+
+	store := newStoreAt(filepath.Join(t.TempDir(), ".ebitris", "settings.json"))
+	assert.Equal(t, Default(), store.LoadSettings())
+
+	settings := Default()
+	settings.Audio.MusicVolume = 0.5
+	settings.Controls.ARR = 0
+	settings.Controls.KeepDASCharge = false
+	settings.Gameplay.BoardWidth = 8
+	settings.Display.Fullscreen = true
+	store.SaveSettings(settings)
+
+	assert.Equal(t, settings, store.LoadSettings())
+}
+
+func TestLoadSettingsFallsBack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		contents string
+		expected func(s *Settings)
+	}{
+		{
+			name:     "missing options keep their defaults",
+			contents: `{"version": 1, "audio": {"musicVolume": 0.3}, "gameplay": {"boardHeight": 18}}`,
+			expected: func(s *Settings) {
+				s.Audio.MusicVolume = 0.3
+				s.Gameplay.BoardHeight = 18
+			},
+		},
+		{
+			name:     "values out of range are clamped",
+			contents: `{"version": 1, "audio": {"effectsVolume": 7}, "gameplay": {"boardWidth": 40, "previewCount": 0}, "display": {"windowScale": 5}}`,
+			expected: func(s *Settings) {
+				s.Audio.EffectsVolume = 1
+				s.Gameplay.BoardWidth = MaxBoardWidth
+				s.Gameplay.PreviewCount = MinPreviewCount
+				s.Display.WindowScale = MinWindowScale
+			},
+		},
+		{
+			name:     "unsupported version",
+			contents: `{"version": 99, "audio": {"musicVolume": 0.3}}`,
+			expected: func(s *Settings) {},
+		},
+		{
+			name:     "not JSON",
+			contents: `settings`,
+			expected: func(s *Settings) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "settings.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+
+			expected := Default()
+			tt.expected(&expected)
+			assert.Equal(t, expected, newStoreAt(path).LoadSettings())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+)
+
+// Easing maps linear progress between 0 and 1 to eased progress.
+type Easing func(t float64) float64
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+func EaseOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// Tween moves a value from one number to another over a fixed number of frames.
+type Tween struct {
+	from, to float64
+	frames   int
+	ease     Easing
+	frame    int
+}
+
+func NewTween(from, to float64, frames int, ease Easing) *Tween {
+	return &Tween{from: from, to: to, frames: max(frames, 1), ease: ease}
+}
+
+// Update advances the tween by one frame.
+func (t *Tween) Update() {
+	if t.frame < t.frames {
+		t.frame++
+	}
+}
+
+// Value returns the eased value for the current frame.
+func (t *Tween) Value() float64 {
+	progress := float64(t.frame) / float64(t.frames)
+	return t.from + (t.to-t.from)*t.ease(progress)
+}
+
+func (t *Tween) Done() bool {
+	return t.frame >= t.frames
+}
+
+// Animation is a timed effect drawn over the gameplay screen.
+type Animation interface {
+	// Update advances the animation by one frame and reports whether it is still running.
+	Update() bool
+	Draw(screen *ebiten.Image)
+}
+
+// Animator plays the effects triggered by gameplay events on top of the board.
+type Animator struct {
+	boardWidth int
+	animations []Animation
+}
+
+func NewAnimator(boardWidth int) *Animator {
+	return &Animator{boardWidth: boardWidth}
+}
+
+// Play starts an animation alongside the ones already running.
+func (a *Animator) Play(animation Animation) {
+	a.animations = append(a.animations, animation)
+}
+
+// HandleEvent starts the effects for a gameplay event. Events without an effect are ignored.
+func (a *Animator) HandleEvent(e event.Event) {
+	switch payload := e.Payload.(type) {
+	case gameevent.PieceLockedPayload:
+		if payload.HardDropRows > 0 {
+			a.Play(newHardDropTrail(payload.Piece, payload.HardDropRows))
+		}
+		a.Play(newLockFlash(payload.Piece))
+	case gameevent.LineClearPayload:
+		if len(payload.Rows) > 0 {
+			a.Play(newLineClearAnimation(payload.Rows, a.boardWidth))
+		}
+	case gameevent.LevelUpPayload:
+		a.Play(newLevelUpBanner(payload.Level, a.boardWidth))
+	}
+}
+
+// Update advances every animation and drops the finished ones.
+func (a *Animator) Update() {
+	running := a.animations[:0]
+	for _, animation := range a.animations {
+		if animation.Update() {
+			running = append(running, animation)
+		}
+	}
+	clear(a.animations[len(running):])
+	a.animations = running
+}
+
+// Draw draws the running animations in the order they started.
+func (a *Animator) Draw(screen *ebiten.Image) {
+	for _, animation := range a.animations {
+		animation.Draw(screen)
+	}
+}
@@ -1,6 +1,7 @@
 package render
 
 import (
+	"fmt"
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -11,7 +12,16 @@ import (
 
 const BlockSize = 30
 
-var borderColor = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+// Cell position of the board's top-left corner in the gameplay layout.
+const (
+	boardOffsetX = 4
+	boardOffsetY = 2
+)
+
+var (
+	borderColor     = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	boardBackground = color.RGBA{R: 20, G: 20, B: 30, A: 255}
+)
 
 func DrawBlock(screen *ebiten.Image, x, y int, rectangleColor color.Color) {
 	pixelX := float32(x * BlockSize)
@@ -26,8 +36,7 @@ func DrawBoard(screen *ebiten.Image, board *tetris.Board, offsetX, offsetY int)
 		for x := 0; x < board.Width; x++ {
 			cellValue := board.Cell(x, y)
 
-			bgColor := color.RGBA{R: 20, G: 20, B: 30, A: 255}
-			DrawBlock(screen, offsetX+x, offsetY+y, bgColor)
+			DrawBlock(screen, offsetX+x, offsetY+y, boardBackground)
 
 			if cellValue != 0 {
 				pieceColor := tetris.GetPieceColor(cellValue)
@@ -55,6 +64,13 @@ func DrawPiece(screen *ebiten.Image, piece *tetris.Piece, offsetX, offsetY int)
 	}
 }
 
+// DrawPiecePreview draws a piece with the top-left of its bounding box at cell (x, y), ignoring its board position.
+func DrawPiecePreview(screen *ebiten.Image, piece *tetris.Piece, x, y int, pieceColor color.Color) {
+	for _, cell := range piece.GetCells() {
+		DrawBlock(screen, x+cell.X, y+cell.Y, pieceColor)
+	}
+}
+
 func DrawText(screen *ebiten.Image, textToDraw string, x, y int, fontFace text.Face) {
 	_, height := text.Measure(textToDraw, fontFace, 0)
 	op := &text.DrawOptions{}
@@ -66,3 +82,35 @@ func DrawText(screen *ebiten.Image, textToDraw string, x, y int, fontFace text.F
 func DrawRectangle(screen *ebiten.Image, x, y, width, height int, col color.Color) {
 	vector.FillRect(screen, float32(x*BlockSize), float32(y*BlockSize), float32(width*BlockSize), float32(height*BlockSize), col, true)
 }
+
+// DrawGameState draws the board, the falling piece with its shadow and the HUD.
+func DrawGameState(screen *ebiten.Image, state *tetris.GameState) {
+	screen.Fill(color.RGBA{R: 10, G: 10, B: 20, A: 255})
+
+	DrawBoard(screen, state.GetBoard(), boardOffsetX, boardOffsetY)
+	// There is no falling piece during the entry delay
+	if current := state.GetCurrentPiece(); current != nil {
+		DrawPiece(screen, state.GetShadowPiece(), boardOffsetX, boardOffsetY)
+		DrawPiece(screen, current, boardOffsetX, boardOffsetY)
+	}
+
+	font := GetDefaultFont(FontMedium)
+
+	DrawText(screen, fmt.Sprintf("Score: %d", state.GetScore()), 1, 3, font)
+	DrawText(screen, fmt.Sprintf("Level: %d", state.GetLevel()), 1, 4, font)
+	DrawText(screen, fmt.Sprintf("Lines: %d", state.GetLinesCleared()), 1, 5, font)
+
+	DrawText(screen, "Next:", 16, 7, font)
+	for i, next := range state.GetNextPieces(state.GetOptions().PreviewCount) {
+		DrawPiecePreview(screen, next, 15, 8+i*3, tetris.GetPieceColor(next.Color))
+	}
+
+	DrawText(screen, "Hold:", 1, 7, font)
+	if held := state.GetHeldPiece(); held != nil {
+		holdColor := tetris.GetPieceColor(held.Color)
+		if !state.CanHold() {
+			holdColor = tetris.GetPieceColor(tetris.PieceShadow)
+		}
+		DrawPiecePreview(screen, held, 0, 9, holdColor)
+	}
+}
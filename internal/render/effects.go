@@ -0,0 +1,203 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/piotrowski/ebitris/internal/tetris"
+)
+
+const (
+	lineFlashFrames    = 16
+	lineCollapseFrames = 14
+	lineFlashPeriod    = 4 // Frames each flash stays on or off
+
+	// LineClearFrames is how long the line clear animation runs. Games should
+	// keep the cleared rows on the board, through tetris.Options.LineClearDelay,
+	// for at least this many frames.
+	LineClearFrames = lineFlashFrames + lineCollapseFrames
+
+	lockFlashFrames     = 10
+	hardDropTrailFrames = 12
+	levelUpBannerFrames = 90
+)
+
+var flashColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+// fade scales a color's opacity by alpha, between 0 and 1.
+func fade(c color.Color, alpha float64) color.Color {
+	r, g, b, a := c.RGBA()
+	return color.RGBA64{
+		R: uint16(float64(r) * alpha),
+		G: uint16(float64(g) * alpha),
+		B: uint16(float64(b) * alpha),
+		A: uint16(float64(a) * alpha),
+	}
+}
+
+// fillCells fills a rectangle given in board cells, which may be fractional.
+func fillCells(screen *ebiten.Image, x, y, width, height float64, c color.Color) {
+	vector.FillRect(screen,
+		float32((boardOffsetX+x)*BlockSize), float32((boardOffsetY+y)*BlockSize),
+		float32(width*BlockSize), float32(height*BlockSize),
+		c, true)
+}
+
+// lineClearAnimation flashes the cleared rows, then wipes them out from the middle.
+type lineClearAnimation struct {
+	rows     []int
+	width    int
+	frame    int
+	collapse *Tween
+}
+
+// newLineClearAnimation animates the visible rows only. Rows cleared in the
+// buffer above the board have negative indices and are not drawn.
+func newLineClearAnimation(rows []int, boardWidth int) *lineClearAnimation {
+	var visible []int
+	for _, y := range rows {
+		if y >= 0 {
+			visible = append(visible, y)
+		}
+	}
+	return &lineClearAnimation{
+		rows:     visible,
+		width:    boardWidth,
+		collapse: NewTween(0, float64(boardWidth)/2, lineCollapseFrames, EaseInQuad),
+	}
+}
+
+func (a *lineClearAnimation) Update() bool {
+	a.frame++
+	if a.frame > lineFlashFrames {
+		a.collapse.Update()
+	}
+	return a.frame < LineClearFrames
+}
+
+func (a *lineClearAnimation) Draw(screen *ebiten.Image) {
+	if a.frame < lineFlashFrames {
+		if (a.frame/lineFlashPeriod)%2 == 0 {
+			for _, y := range a.rows {
+				fillCells(screen, 0, float64(y), float64(a.width), 1, fade(flashColor, 0.7))
+			}
+		}
+		return
+	}
+
+	half := a.collapse.Value()
+	center := float64(a.width) / 2
+	for _, y := range a.rows {
+		fillCells(screen, center-half, float64(y), half*2, 1, boardBackground)
+	}
+}
+
+// lockFlash briefly lights up the cells of a piece that just locked.
+type lockFlash struct {
+	piece *tetris.Piece
+	alpha *Tween
+}
+
+func newLockFlash(piece *tetris.Piece) *lockFlash {
+	return &lockFlash{piece: piece, alpha: NewTween(0.8, 0, lockFlashFrames, EaseOutQuad)}
+}
+
+func (a *lockFlash) Update() bool {
+	a.alpha.Update()
+	return !a.alpha.Done()
+}
+
+func (a *lockFlash) Draw(screen *ebiten.Image) {
+	c := fade(flashColor, a.alpha.Value())
+	for _, cell := range a.piece.GetCells() {
+		y := a.piece.Y + cell.Y
+		if y < 0 {
+			continue
+		}
+		fillCells(screen, float64(a.piece.X+cell.X), float64(y), 1, 1, c)
+	}
+}
+
+// hardDropTrail leaves a fading streak above each column a hard dropped piece fell through.
+type hardDropTrail struct {
+	piece *tetris.Piece
+	rows  int
+	alpha *Tween
+}
+
+func newHardDropTrail(piece *tetris.Piece, rows int) *hardDropTrail {
+	return &hardDropTrail{piece: piece, rows: rows, alpha: NewTween(0.5, 0, hardDropTrailFrames, Linear)}
+}
+
+func (a *hardDropTrail) Update() bool {
+	a.alpha.Update()
+	return !a.alpha.Done()
+}
+
+func (a *hardDropTrail) Draw(screen *ebiten.Image) {
+	// The trail of each column ends at the topmost cell of the piece in it
+	tops := map[int]int{}
+	for _, cell := range a.piece.GetCells() {
+		x, y := a.piece.X+cell.X, a.piece.Y+cell.Y
+		if top, ok := tops[x]; !ok || y < top {
+			tops[x] = y
+		}
+	}
+
+	c := fade(tetris.GetPieceColor(a.piece.Color), a.alpha.Value())
+	for x, top := range tops {
+		start := max(top-a.rows, 0)
+		if start >= top {
+			continue
+		}
+		fillCells(screen, float64(x)+0.25, float64(start), 0.5, float64(top-start), c)
+	}
+}
+
+// levelUpBanner slides a level announcement down over the board and fades it out.
+type levelUpBanner struct {
+	label   string
+	width   int
+	frame   int
+	slide   *Tween
+	fadeOut *Tween
+}
+
+func newLevelUpBanner(level, boardWidth int) *levelUpBanner {
+	return &levelUpBanner{
+		label:   fmt.Sprintf("LEVEL %d", level),
+		width:   boardWidth,
+		slide:   NewTween(3, 6, levelUpBannerFrames/3, EaseOutQuad),
+		fadeOut: NewTween(1, 0, levelUpBannerFrames/3, Linear),
+	}
+}
+
+func (a *levelUpBanner) Update() bool {
+	a.frame++
+	a.slide.Update()
+	// Hold the banner for the middle third before fading it out
+	if a.frame > levelUpBannerFrames*2/3 {
+		a.fadeOut.Update()
+	}
+	return a.frame < levelUpBannerFrames
+}
+
+func (a *levelUpBanner) Draw(screen *ebiten.Image) {
+	font := GetDefaultFont(FontLarge)
+	width, height := text.Measure(a.label, font, 0)
+
+	alpha := a.fadeOut.Value()
+	y := a.slide.Value()
+	fillCells(screen, 0, y, float64(a.width), 1.5, fade(boardBackground, alpha*0.8))
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(
+		(boardOffsetX+float64(a.width)/2)*BlockSize-width/2,
+		(boardOffsetY+y+0.75)*BlockSize-height/2,
+	)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	text.Draw(screen, a.label, font, op)
+}
@@ -16,16 +16,22 @@ func NewMenu(items []string) *Menu {
 	return &Menu{items: items}
 }
 
-// HandleInput handles Up/Down/Enter navigation. Returns true if Enter was pressed.
+// HandleInput handles MenuUp/MenuDown/Confirm navigation. Returns true if Confirm was pressed.
 func (m *Menu) HandleInput(im *input.InputManager) bool {
 	n := len(m.items)
-	if im.IsKeyJustPressed(ebiten.KeyDown) {
+	if im.IsActionJustPressed(input.ActionMenuDown) {
 		m.focus = (m.focus + 1) % n
 	}
-	if im.IsKeyJustPressed(ebiten.KeyUp) {
+	if im.IsActionJustPressed(input.ActionMenuUp) {
 		m.focus = (m.focus - 1 + n) % n
 	}
-	return im.IsKeyJustPressed(ebiten.KeyEnter)
+	return im.IsActionJustPressed(input.ActionConfirm)
+}
+
+// SetItems replaces the item labels, keeping the focus on the same index.
+func (m *Menu) SetItems(items []string) {
+	m.items = items
+	m.focus = min(m.focus, len(items)-1)
 }
 
 // Selected returns the index of the currently focused item.
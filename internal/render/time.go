@@ -0,0 +1,23 @@
+package render
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatTime formats a game time as minutes, seconds and milliseconds, like 1:05.250.
+func FormatTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+}
+
+// FormatDelta formats the difference to a reference time with its sign, like
+// -0.500 when ahead of it and +1.234 when behind.
+func FormatDelta(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%s%d.%03d", sign, ms/1000, ms%1000)
+}
@@ -0,0 +1,139 @@
+package controls
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/render"
+)
+
+// captureFrames is how long the scene waits for a key to add before giving up,
+// so cancelling does not take a key away from the ones that can be bound.
+const captureFrames = 5 * ebiten.DefaultTPS
+
+// ControlsScene lists every action with its keys and lets the player add keys
+// to any of them or clear them. Changes are applied and saved when leaving.
+type ControlsScene struct {
+	emitter       event.Emitter
+	input         *input.InputManager
+	bindingsSaver input.BindingsSaver
+	menu          *render.Menu
+
+	actions   []input.Action
+	bindings  input.Bindings
+	capturing int // Frames left to press a key to add to the focused action, 0 when not capturing
+}
+
+func NewControlsScene(emitter event.Emitter, im *input.InputManager, bindingsSaver input.BindingsSaver) *ControlsScene {
+	s := &ControlsScene{
+		emitter:       emitter,
+		input:         im,
+		bindingsSaver: bindingsSaver,
+		actions:       input.Actions(),
+		bindings:      im.Bindings(),
+	}
+	s.menu = render.NewMenu(s.labels())
+	return s
+}
+
+func (s *ControlsScene) Update() error {
+	if s.capturing > 0 {
+		s.capture()
+		return nil
+	}
+
+	if s.input.IsActionJustPressed(input.ActionBack) {
+		s.leave()
+		return nil
+	}
+
+	if s.input.IsKeyJustPressed(ebiten.KeyBackspace) || s.input.IsKeyJustPressed(ebiten.KeyDelete) {
+		s.clear()
+		return nil
+	}
+
+	if s.menu.HandleInput(s.input) {
+		switch selected := s.menu.Selected(); {
+		case selected < len(s.actions):
+			s.capturing = captureFrames
+		case selected == len(s.actions):
+			s.bindings = input.DefaultBindings()
+			s.menu.SetItems(s.labels())
+		default:
+			s.leave()
+		}
+	}
+
+	return nil
+}
+
+// capture adds the next key pressed to the focused action. Any key can be
+// added, Escape too; pressing one the action already has just stops capturing.
+func (s *ControlsScene) capture() {
+	s.capturing--
+	keys := s.input.GetJustPressedKeys()
+	if len(keys) == 0 {
+		return
+	}
+
+	s.capturing = 0
+	action := s.actions[s.menu.Selected()]
+	if slices.Contains(s.bindings[action], keys[0]) {
+		return
+	}
+	s.bindings[action] = append(s.bindings[action], keys[0])
+	s.menu.SetItems(s.labels())
+}
+
+// clear removes every key of the focused action. The scene keeps navigating
+// with the bindings in use until it leaves, and an action still without keys
+// then gets its defaults back, so clearing cannot lock the player out.
+func (s *ControlsScene) clear() {
+	selected := s.menu.Selected()
+	if selected >= len(s.actions) {
+		return
+	}
+	s.bindings[s.actions[selected]] = nil
+	s.menu.SetItems(s.labels())
+}
+
+func (s *ControlsScene) leave() {
+	s.input.SetBindings(s.bindings)
+	s.bindingsSaver.SaveBindings(s.input.Bindings())
+	s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
+}
+
+func (s *ControlsScene) labels() []string {
+	labels := make([]string, 0, len(s.actions)+2)
+	for _, action := range s.actions {
+		names := make([]string, len(s.bindings[action]))
+		for i, key := range s.bindings[action] {
+			names[i] = key.String()
+		}
+		labels = append(labels, fmt.Sprintf("%s: %s", action, strings.Join(names, ", ")))
+	}
+	return append(labels, "Reset to Defaults", "Back")
+}
+
+func (s *ControlsScene) Draw(screen *ebiten.Image) {
+	fontLarge := render.GetDefaultFont(render.FontLarge)
+	fontMedium := render.GetDefaultFont(render.FontMedium)
+
+	render.DrawText(screen, "Controls", 5, 3, fontLarge)
+	s.menu.Draw(screen, 5, 6)
+
+	if s.capturing > 0 {
+		seconds := (s.capturing + ebiten.DefaultTPS - 1) / ebiten.DefaultTPS
+		render.DrawText(screen, fmt.Sprintf("Press a key to add to %s (%ds)", s.actions[s.menu.Selected()], seconds), 2, 22, fontMedium)
+	} else {
+		render.DrawText(screen, "ENTER to add a key, BACKSPACE to clear", 2, 22, fontMedium)
+		render.DrawText(screen, "ESC to save and exit, unbound actions keep their defaults", 2, 23, fontMedium)
+	}
+}
+
+func (s *ControlsScene) OnEnter() {}
+func (s *ControlsScene) OnExit()  {}
@@ -0,0 +1,116 @@
+package controls
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmitter struct {
+	events []event.EventType
+}
+
+func (e *recordingEmitter) Emit(ev event.Event) {
+	e.events = append(e.events, ev.Type)
+}
+
+type fakeBindingsSaver struct {
+	saved []input.Bindings
+}
+
+func (s *fakeBindingsSaver) SaveBindings(bindings input.Bindings) {
+	s.saved = append(s.saved, bindings)
+}
+
+// press plays one frame with the keys held, then one with them released.
+func press(t *testing.T, scene *ControlsScene, source *input.FakeSource, keys ...ebiten.Key) {
+	t.Helper()
+
+	source.Frame(keys...)
+	require.NoError(t, scene.Update())
+	source.Frame()
+	require.NoError(t, scene.Update())
+}
+
+func TestRebindRotateCW(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		keys     []ebiten.Key
+		expected []ebiten.Key
+	}{
+		{
+			name:     "added key keeps the defaults",
+			keys:     []ebiten.Key{ebiten.KeyEnter, ebiten.KeyZ},
+			expected: []ebiten.Key{ebiten.KeyUp, ebiten.KeyX, ebiten.KeyZ},
+		},
+		{
+			name:     "escape can be added",
+			keys:     []ebiten.Key{ebiten.KeyEnter, ebiten.KeyEscape},
+			expected: []ebiten.Key{ebiten.KeyUp, ebiten.KeyX, ebiten.KeyEscape},
+		},
+		{
+			name:     "key the action already has",
+			keys:     []ebiten.Key{ebiten.KeyEnter, ebiten.KeyX},
+			expected: []ebiten.Key{ebiten.KeyUp, ebiten.KeyX},
+		},
+		{
+			name:     "cleared then added",
+			keys:     []ebiten.Key{ebiten.KeyBackspace, ebiten.KeyEnter, ebiten.KeyZ},
+			expected: []ebiten.Key{ebiten.KeyZ},
+		},
+		{
+			name:     "cleared and left without keys",
+			keys:     []ebiten.Key{ebiten.KeyDelete},
+			expected: []ebiten.Key{ebiten.KeyUp, ebiten.KeyX},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := input.NewFakeSource()
+			im := input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings())
+			emitter := &recordingEmitter{}
+			saver := &fakeBindingsSaver{}
+			scene := NewControlsScene(emitter, im, saver)
+
+			for range input.ActionRotateCW {
+				press(t, scene, source, ebiten.KeyDown)
+			}
+			for _, key := range tt.keys {
+				press(t, scene, source, key)
+			}
+			press(t, scene, source, ebiten.KeyEscape)
+
+			assert.Equal(t, []event.EventType{event.EventTypeMainMenu}, emitter.events)
+			require.Len(t, saver.saved, 1)
+			assert.Equal(t, tt.expected, saver.saved[0][input.ActionRotateCW])
+			assert.Equal(t, tt.expected, im.Bindings()[input.ActionRotateCW])
+		})
+	}
+}
+
+func TestCaptureTimesOut(t *testing.T) {
+	t.Parallel()
+
+	source := input.NewFakeSource()
+	emitter := &recordingEmitter{}
+	scene := NewControlsScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), &fakeBindingsSaver{})
+
+	press(t, scene, source, ebiten.KeyEnter)
+	for range captureFrames {
+		require.NoError(t, scene.Update())
+	}
+	assert.Zero(t, scene.capturing)
+
+	press(t, scene, source, ebiten.KeyEscape)
+	assert.Equal(t, []event.EventType{event.EventTypeMainMenu}, emitter.events)
+	assert.Equal(t, input.DefaultBindings()[input.ActionMoveLeft], scene.bindings[input.ActionMoveLeft])
+}
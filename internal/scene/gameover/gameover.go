@@ -4,9 +4,12 @@ import (
 	"fmt"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
 	"github.com/piotrowski/ebitris/internal/pkg/event"
 	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/score"
 	"github.com/piotrowski/ebitris/internal/render"
+	"github.com/piotrowski/ebitris/internal/sim"
 )
 
 type scoreSaver interface {
@@ -14,31 +17,119 @@ type scoreSaver interface {
 }
 
 type GameOverScene struct {
-	emitter    event.Emitter
-	scoreSaver scoreSaver
+	emitter event.Emitter
+	input   *input.InputManager
 
-	input *input.InputManager
-	score int
-	level int
-	lines int
+	title   string
+	summary []string              // Lines shown under the title
+	save    func(initials string) // nil when the result cannot be saved
 
-	menu *render.Menu
+	menu    *render.Menu
+	actions []func() // Run when the menu item with the same index is confirmed
 
 	isInitialsModeActive bool
 	initials             string
+	letter               rune // Letter picked with MenuUp/MenuDown, for players without a keyboard
 }
 
-func NewGameOverScene(emitter event.Emitter, scoreSaver scoreSaver, score, level, lines int) *GameOverScene {
-	return &GameOverScene{
-		emitter:    emitter,
-		scoreSaver: scoreSaver,
-		score:      score,
-		level:      level,
-		lines:      lines,
-		input:      input.NewInputManager(),
+func NewGameOverScene(emitter event.Emitter, im *input.InputManager, scoreSaver scoreSaver, score, level, lines int) *GameOverScene {
+	s := &GameOverScene{
+		emitter: emitter,
+		input:   im,
+		letter:  'A',
+		title:   "Game Over",
+		summary: []string{
+			fmt.Sprintf("Score: %d", score),
+			fmt.Sprintf("Level: %d", level),
+			fmt.Sprintf("Lines: %d", lines),
+		},
+		save: func(initials string) {
+			scoreSaver.SaveScore(initials, score, level, lines)
+		},
+	}
+	s.buildMenu()
+	return s
+}
 
-		menu: render.NewMenu([]string{"Save Score", "Restart", "Main Menu"}),
+// NewUltraGameOverScene shows the score reached before time ran out, or
+// before the stack topped out. Either way it goes on the Ultra leaderboard.
+func NewUltraGameOverScene(emitter event.Emitter, im *input.InputManager, scoreSaver scoreSaver, result gameevent.GameOverPayload) *GameOverScene {
+	s := NewGameOverScene(emitter, im, scoreSaver, result.Score, result.Level, result.Lines)
+	if result.Complete {
+		s.title = "Time's Up!"
 	}
+	s.summary = append(s.summary, fmt.Sprintf("Pieces: %d", result.Pieces))
+	return s
+}
+
+// NewSprintGameOverScene shows how a sprint went against the line ticks of
+// the personal best, which is empty when there is none. Only finished
+// sprints can be saved.
+func NewSprintGameOverScene(emitter event.Emitter, im *input.InputManager, sprintSaver score.SprintSaver, result gameevent.GameOverPayload, best []int) *GameOverScene {
+	s := &GameOverScene{
+		emitter: emitter,
+		input:   im,
+		letter:  'A',
+		title:   "Game Over",
+		summary: []string{fmt.Sprintf("Lines: %d/%d", len(result.LineTicks), sim.SprintLines)},
+	}
+
+	if result.Complete {
+		s.title = "Finished!"
+		s.summary = sprintSummary(result, best)
+		if len(best) == 0 || result.Ticks < best[len(best)-1] {
+			s.title = "New Personal Best!"
+		}
+		s.save = func(initials string) {
+			sprintSaver.SaveSprint(score.SprintEntry{
+				Initials:  initials,
+				Ticks:     result.Ticks,
+				LineTicks: result.LineTicks,
+				Pieces:    result.Pieces,
+			})
+		}
+	}
+
+	s.buildMenu()
+	return s
+}
+
+// sprintSummary lists the final time and every split, each compared to the personal best.
+func sprintSummary(result gameevent.GameOverPayload, best []int) []string {
+	compare := func(ticks, bestTicks int) string {
+		return render.FormatTime(sim.Duration(ticks)) + " " + render.FormatDelta(sim.Duration(ticks-bestTicks))
+	}
+
+	summary := []string{"Time: " + render.FormatTime(sim.Duration(result.Ticks))}
+	if len(best) > 0 {
+		summary[0] = "Time: " + compare(result.Ticks, best[len(best)-1])
+	}
+	summary = append(summary, fmt.Sprintf("Pieces: %d (%.2f/s)", result.Pieces, float64(result.Pieces)/sim.Duration(result.Ticks).Seconds()))
+
+	bestSplits := sim.Splits(best)
+	for i, split := range sim.Splits(result.LineTicks) {
+		line := fmt.Sprintf("%d lines: %s", (i+1)*sim.SprintSplitLines, render.FormatTime(sim.Duration(split)))
+		if i < len(bestSplits) {
+			line = fmt.Sprintf("%d lines: %s", (i+1)*sim.SprintSplitLines, compare(split, bestSplits[i]))
+		}
+		summary = append(summary, line)
+	}
+	return summary
+}
+
+// buildMenu offers saving only when there is something to save.
+func (s *GameOverScene) buildMenu() {
+	var items []string
+	if s.save != nil {
+		items = append(items, "Save Score")
+		s.actions = append(s.actions, func() { s.isInitialsModeActive = true })
+	}
+	items = append(items, "Restart", "Main Menu")
+	s.actions = append(s.actions,
+		func() { s.emitter.Emit(event.Event{Type: event.EventTypeStartGame}) },
+		func() { s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu}) },
+	)
+	s.menu = render.NewMenu(items)
 }
 
 func (s *GameOverScene) Update() error {
@@ -47,35 +138,41 @@ func (s *GameOverScene) Update() error {
 	}
 
 	if s.menu.HandleInput(s.input) {
-		switch s.menu.Selected() {
-		case 0:
-			s.isInitialsModeActive = true
-		case 1:
-			s.emitter.Emit(event.Event{Type: event.EventTypeStartGame})
-		case 2:
-			s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
-		}
+		s.actions[s.menu.Selected()]()
 	}
 
 	return nil
 }
 
 func (s *GameOverScene) initialsMode() error {
-	if s.input.IsKeyJustPressed(ebiten.KeyEnter) {
-		s.scoreSaver.SaveScore(s.initials, s.score, s.level, s.lines)
+	if s.input.IsActionJustPressed(input.ActionConfirm) {
+		s.save(s.initials)
 		s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
 		return nil
 	}
 
-	if s.input.IsKeyJustPressed(ebiten.KeyEscape) {
+	if s.input.IsActionJustPressed(input.ActionBack) {
 		s.isInitialsModeActive = false
 		s.initials = ""
 		return nil
 	}
 
-	if s.input.IsKeyJustPressed(ebiten.KeyBackspace) && s.initials != "" {
+	switch {
+	case s.input.IsActionJustPressed(input.ActionMenuUp):
+		s.letter = 'A' + (s.letter-'A'+1)%26
+	case s.input.IsActionJustPressed(input.ActionMenuDown):
+		s.letter = 'A' + (s.letter-'A'+25)%26
+	case s.input.IsActionJustPressed(input.ActionMoveRight):
+		if len(s.initials) < 3 {
+			s.initials += string(s.letter)
+		}
+	case s.input.IsActionJustPressed(input.ActionMoveLeft):
+		if s.initials != "" {
+			s.initials = s.initials[:len(s.initials)-1]
+		}
+	case s.input.IsKeyJustPressed(ebiten.KeyBackspace) && s.initials != "":
 		s.initials = s.initials[:len(s.initials)-1]
-	} else {
+	default:
 		for _, key := range s.input.GetJustPressedKeys() {
 			if key >= ebiten.KeyA && key <= ebiten.KeyZ && len(s.initials) < 3 {
 				s.initials += string(rune('A' + (key - ebiten.KeyA)))
@@ -90,17 +187,19 @@ func (s *GameOverScene) Draw(screen *ebiten.Image) {
 	fontLarge := render.GetDefaultFont(render.FontLarge)
 	fontMedium := render.GetDefaultFont(render.FontMedium)
 
-	render.DrawText(screen, "Game Over", 5, 5, fontLarge)
-	render.DrawText(screen, fmt.Sprintf("Score: %d", s.score), 5, 6, fontMedium)
-	render.DrawText(screen, fmt.Sprintf("Level: %d", s.level), 5, 7, fontMedium)
-	render.DrawText(screen, fmt.Sprintf("Lines: %d", s.lines), 5, 8, fontMedium)
+	render.DrawText(screen, s.title, 5, 5, fontLarge)
+	for i, line := range s.summary {
+		render.DrawText(screen, line, 5, 6+i, fontMedium)
+	}
 
-	s.menu.Draw(screen, 5, 10)
+	menuY := 7 + len(s.summary)
+	s.menu.Draw(screen, 5, menuY)
 
 	if s.isInitialsModeActive {
-		render.DrawText(screen, fmt.Sprintf("Enter Initials: %s", s.initials), 5, 15, fontMedium)
-		render.DrawText(screen, "Press ENTER to save", 5, 16, fontMedium)
-		render.DrawText(screen, "Press ESC to cancel", 5, 17, fontMedium)
+		render.DrawText(screen, fmt.Sprintf("Enter Initials: %s [%c]", s.initials, s.letter), 5, menuY+5, fontMedium)
+		render.DrawText(screen, "Press ENTER to save", 5, menuY+6, fontMedium)
+		render.DrawText(screen, "Press ESC to cancel", 5, menuY+7, fontMedium)
+		render.DrawText(screen, "UP/DOWN pick a letter, RIGHT adds it, LEFT erases", 5, menuY+8, fontMedium)
 	}
 }
 
@@ -0,0 +1,225 @@
+package gameover
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/score"
+	"github.com/piotrowski/ebitris/internal/sim"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingEmitter struct {
+	events []event.EventType
+}
+
+func (e *recordingEmitter) Emit(ev event.Event) {
+	e.events = append(e.events, ev.Type)
+}
+
+type savedScore struct {
+	initials            string
+	score, level, lines int
+}
+
+type fakeScoreSaver struct {
+	saved []savedScore
+}
+
+func (s *fakeScoreSaver) SaveScore(initials string, score, level, lines int) {
+	s.saved = append(s.saved, savedScore{initials, score, level, lines})
+}
+
+// press plays one frame with the keys held, then one with them released.
+func press(t *testing.T, scene *GameOverScene, source *input.FakeSource, keys ...ebiten.Key) {
+	t.Helper()
+
+	source.Frame(keys...)
+	assert.NoError(t, scene.Update())
+	source.Frame()
+	assert.NoError(t, scene.Update())
+}
+
+func TestInitialsEntry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		keys     []ebiten.Key
+		expected string
+	}{
+		{
+			name:     "typed letters",
+			keys:     []ebiten.Key{ebiten.KeyP, ebiten.KeyI, ebiten.KeyO},
+			expected: "PIO",
+		},
+		{
+			name:     "at most three letters",
+			keys:     []ebiten.Key{ebiten.KeyA, ebiten.KeyB, ebiten.KeyC, ebiten.KeyD},
+			expected: "ABC",
+		},
+		{
+			name:     "backspace erases",
+			keys:     []ebiten.Key{ebiten.KeyA, ebiten.KeyB, ebiten.KeyBackspace, ebiten.KeyC},
+			expected: "AC",
+		},
+		{
+			name:     "letter picker",
+			keys:     []ebiten.Key{ebiten.KeyRight, ebiten.KeyUp, ebiten.KeyUp, ebiten.KeyRight, ebiten.KeyDown, ebiten.KeyDown, ebiten.KeyDown, ebiten.KeyRight},
+			expected: "ACZ",
+		},
+		{
+			name:     "picker erases with left",
+			keys:     []ebiten.Key{ebiten.KeyRight, ebiten.KeyRight, ebiten.KeyLeft},
+			expected: "A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := input.NewFakeSource()
+			emitter := &recordingEmitter{}
+			saver := &fakeScoreSaver{}
+			scene := NewGameOverScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), saver, 1200, 3, 25)
+
+			press(t, scene, source, ebiten.KeyEnter) // Save Score
+			for _, key := range tt.keys {
+				press(t, scene, source, key)
+			}
+			assert.Empty(t, saver.saved)
+
+			press(t, scene, source, ebiten.KeyEnter)
+			assert.Equal(t, []savedScore{{tt.expected, 1200, 3, 25}}, saver.saved)
+			assert.Equal(t, []event.EventType{event.EventTypeMainMenu}, emitter.events)
+		})
+	}
+}
+
+func TestInitialsEntryCancel(t *testing.T) {
+	t.Parallel()
+
+	source := input.NewFakeSource()
+	emitter := &recordingEmitter{}
+	saver := &fakeScoreSaver{}
+	scene := NewGameOverScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), saver, 1200, 3, 25)
+
+	press(t, scene, source, ebiten.KeyEnter)
+	press(t, scene, source, ebiten.KeyQ)
+	press(t, scene, source, ebiten.KeyEscape)
+	assert.False(t, scene.isInitialsModeActive)
+	assert.Empty(t, scene.initials)
+
+	press(t, scene, source, ebiten.KeyDown) // Restart
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Empty(t, saver.saved)
+	assert.Equal(t, []event.EventType{event.EventTypeStartGame}, emitter.events)
+}
+
+func TestUltraGameOver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		result gameevent.GameOverPayload
+		title  string
+	}{
+		{name: "time is up", result: gameevent.GameOverPayload{Mode: sim.ModeUltra, Score: 5400, Level: 4, Lines: 32, Pieces: 90, Complete: true}, title: "Time's Up!"},
+		{name: "topped out first", result: gameevent.GameOverPayload{Mode: sim.ModeUltra, Score: 5400, Level: 4, Lines: 32, Pieces: 90}, title: "Game Over"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := input.NewFakeSource()
+			saver := &fakeScoreSaver{}
+			scene := NewUltraGameOverScene(&recordingEmitter{}, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), saver, tt.result)
+
+			assert.Equal(t, tt.title, scene.title)
+			assert.Contains(t, scene.summary, "Pieces: 90")
+
+			press(t, scene, source, ebiten.KeyEnter) // Save Score
+			for _, key := range []ebiten.Key{ebiten.KeyU, ebiten.KeyL, ebiten.KeyT} {
+				press(t, scene, source, key)
+			}
+			press(t, scene, source, ebiten.KeyEnter)
+			assert.Equal(t, []savedScore{{"ULT", 5400, 4, 32}}, saver.saved)
+		})
+	}
+}
+
+type fakeSprintSaver struct {
+	saved []score.SprintEntry
+}
+
+func (s *fakeSprintSaver) SaveSprint(entry score.SprintEntry) {
+	s.saved = append(s.saved, entry)
+}
+
+func TestSprintGameOver(t *testing.T) {
+	t.Parallel()
+
+	lineTicks := make([]int, sim.SprintLines)
+	for i := range lineTicks {
+		lineTicks[i] = (i + 1) * 60
+	}
+	finished := gameevent.GameOverPayload{Mode: sim.ModeSprint, Complete: true, Ticks: 2400, LineTicks: lineTicks, Pieces: 100}
+	fasterBest := make([]int, sim.SprintLines)
+	slowerBest := make([]int, sim.SprintLines)
+	for i := range fasterBest {
+		fasterBest[i] = (i + 1) * 50
+		slowerBest[i] = (i + 1) * 70
+	}
+
+	tests := []struct {
+		name      string
+		result    gameevent.GameOverPayload
+		best      []int
+		title     string
+		canSave   bool
+		summaries int
+	}{
+		{name: "first sprint is a personal best", result: finished, title: "New Personal Best!", canSave: true, summaries: 6},
+		{name: "slower than the best", result: finished, best: fasterBest, title: "Finished!", canSave: true, summaries: 6},
+		{name: "faster than the best", result: finished, best: slowerBest, title: "New Personal Best!", canSave: true, summaries: 6},
+		{name: "tying the best", result: finished, best: lineTicks, title: "Finished!", canSave: true, summaries: 6},
+		{
+			name:      "topped out before the goal",
+			result:    gameevent.GameOverPayload{Mode: sim.ModeSprint, LineTicks: lineTicks[:12], Ticks: 1000},
+			title:     "Game Over",
+			canSave:   false,
+			summaries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := input.NewFakeSource()
+			emitter := &recordingEmitter{}
+			saver := &fakeSprintSaver{}
+			scene := NewSprintGameOverScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), saver, tt.result, tt.best)
+
+			assert.Equal(t, tt.title, scene.title)
+			assert.Len(t, scene.summary, tt.summaries)
+
+			press(t, scene, source, ebiten.KeyEnter)
+			if !tt.canSave {
+				assert.Equal(t, []event.EventType{event.EventTypeStartGame}, emitter.events, "restart is the first item")
+				return
+			}
+
+			for _, key := range []ebiten.Key{ebiten.KeyR, ebiten.KeyU, ebiten.KeyN} {
+				press(t, scene, source, key)
+			}
+			press(t, scene, source, ebiten.KeyEnter)
+			assert.Equal(t, []score.SprintEntry{{Initials: "RUN", Ticks: 2400, LineTicks: lineTicks, Pieces: 100}}, saver.saved)
+		})
+	}
+}
@@ -2,97 +2,261 @@ package gameplay
 
 import (
 	"fmt"
-	"image/color"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
 	"github.com/piotrowski/ebitris/internal/pkg/event"
 	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/replay"
 	"github.com/piotrowski/ebitris/internal/render"
+	"github.com/piotrowski/ebitris/internal/sim"
 	"github.com/piotrowski/ebitris/internal/tetris"
 )
 
+// Size of the standard board. Modes with a leaderboard are always played on
+// it with the default preview, so every entry was played under the same rules.
+const (
+	standardWidth  = 10
+	standardHeight = 20
+)
+
 type GameplayScene struct {
-	emitter event.Emitter
-	state   *tetris.GameState
-	input   *input.InputManager
+	emitter     event.Emitter
+	replaySaver replay.Saver
+
+	sim      *sim.Simulation
+	input    *input.InputManager
+	recorder *replay.Recorder
+	animator *render.Animator
+	finished bool
+
+	best []int // Line ticks of the personal best sprint, to pace against
+}
+
+func NewStandardGameplayScene(emitter event.Emitter, im *input.InputManager, replaySaver replay.Saver) *GameplayScene {
+	return NewGameplayScene(emitter, im, replaySaver, standardWidth, standardHeight, tetris.DefaultPreviewCount)
+}
+
+// NewGameplayScene starts a Marathon game on a board of the given size,
+// showing previewCount upcoming pieces.
+func NewGameplayScene(emitter event.Emitter, im *input.InputManager, replaySaver replay.Saver, width, height, previewCount int) *GameplayScene {
+	return newGameplayScene(emitter, im, replaySaver, sim.ModeMarathon, width, height, previewCount)
+}
+
+// NewSprintGameplayScene starts a 40 line sprint on the standard board, paced
+// against the line ticks of the personal best or without a pace when best is empty.
+func NewSprintGameplayScene(emitter event.Emitter, im *input.InputManager, replaySaver replay.Saver, best []int) *GameplayScene {
+	s := newGameplayScene(emitter, im, replaySaver, sim.ModeSprint, standardWidth, standardHeight, tetris.DefaultPreviewCount)
+	s.best = best
+	return s
 }
 
-func NewStandardGameplayScene(emitter event.Emitter) *GameplayScene {
-	return NewGameplayScene(emitter, 10, 20)
+// NewUltraGameplayScene starts a game on the standard board scored against
+// the clock, which ends it after sim.UltraTicks.
+func NewUltraGameplayScene(emitter event.Emitter, im *input.InputManager, replaySaver replay.Saver) *GameplayScene {
+	return newGameplayScene(emitter, im, replaySaver, sim.ModeUltra, standardWidth, standardHeight, tetris.DefaultPreviewCount)
 }
 
-func NewGameplayScene(emitter event.Emitter, width, height int) *GameplayScene {
+func newGameplayScene(emitter event.Emitter, im *input.InputManager, replaySaver replay.Saver, mode sim.Mode, width, height, previewCount int) *GameplayScene {
+	game := sim.New(mode, tetris.Options{
+		Width:          width,
+		Height:         height,
+		Seed:           uint64(time.Now().UnixNano()),
+		RotationSystem: tetris.NewSRS(),
+		Randomizer:     tetris.RandomizerSevenBag,
+		Gravity:        tetris.GravityGuideline,
+		PreviewCount:   previewCount,
+		LockDelay:      tetris.Frames(tetris.DefaultLockDelay),
+		LockReset:      tetris.LockResetMove,
+		MaxLockResets:  tetris.DefaultMaxLockResets,
+		LineClearDelay: render.LineClearFrames, // Keep cleared rows until their animation ends
+	})
+
 	return &GameplayScene{
-		emitter: emitter,
-		state:   tetris.NewGameState(width, height),
-		input:   input.NewInputManager(),
+		emitter:     emitter,
+		replaySaver: replaySaver,
+		sim:         game,
+		input:       im,
+		recorder:    replay.NewRecorder(mode, game.State().GetOptions()),
+		animator:    render.NewAnimator(width),
 	}
 }
 
 func (s *GameplayScene) Update() error {
-	var blockedMoved bool
-	if s.input.ShouldMove(ebiten.KeyLeft) {
-		blockedMoved = s.state.MoveLeft()
-	}
-	if s.input.ShouldMove(ebiten.KeyRight) {
-		blockedMoved = s.state.MoveRight()
+	if s.input.IsActionJustPressed(input.ActionPause) {
+		s.emitter.Emit(event.Event{Type: event.EventTypePause})
+		return nil
 	}
-	if s.input.IsKeyJustPressed(ebiten.KeyUp) {
-		blockedMoved = s.state.Rotate()
+
+	s.animator.Update()
+
+	if s.sim.Done() {
+		s.finish()
+		return nil
 	}
-	if s.input.ShouldMove(ebiten.KeyDown) {
-		blockedMoved = s.state.MoveDown()
+
+	actions := s.readActions()
+	s.recorder.Record(actions)
+
+	result := s.sim.Step(actions)
+
+	var blockMoved bool
+	for _, action := range result.Applied {
+		switch action {
+		case tetris.ActionHardDrop:
+			s.emitter.Emit(event.Event{Type: event.EventTypeBlockPlaced})
+		case tetris.ActionMoveLeft, tetris.ActionMoveRight, tetris.ActionRotate,
+			tetris.ActionRotateCCW, tetris.ActionMoveDown, tetris.ActionHold:
+			blockMoved = true
+		}
 	}
 
-	if blockedMoved {
+	if blockMoved {
 		s.emitter.Emit(event.Event{Type: event.EventTypeBlockMovedByPlayer})
 	}
 
-	if s.input.IsKeyJustPressed(ebiten.KeySpace) {
-		s.state.HardDrop()
-		s.emitter.Emit(event.Event{Type: event.EventTypeBlockPlaced})
+	s.emitLocks(result.Locks)
+	if result.DangerChanged {
+		s.emitter.Emit(event.Event{Type: event.EventTypeDanger, Payload: gameevent.DangerPayload{Danger: result.Danger}})
 	}
-	if s.input.IsKeyJustPressed(ebiten.KeyEscape) {
-		s.emitter.Emit(event.Event{Type: event.EventTypePause})
-		return nil
+
+	return nil
+}
+
+// emitLocks publishes the pieces locked this frame and starts their animations.
+func (s *GameplayScene) emitLocks(locks []tetris.LockResult) {
+	for _, lock := range locks {
+		s.input.ResetCharge()
+		for _, e := range gameevent.LockEvents(lock) {
+			s.emitter.Emit(e)
+			s.animator.HandleEvent(e)
+		}
+	}
+}
+
+// readActions translates this frame's key presses into game actions. Held
+// keys can repeat an action several times in one frame.
+func (s *GameplayScene) readActions() []tetris.Action {
+	board := s.sim.State().GetBoard()
+
+	var actions []tetris.Action
+	for range s.input.Repeats(input.ActionMoveLeft, board.Width) {
+		actions = append(actions, tetris.ActionMoveLeft)
+	}
+	for range s.input.Repeats(input.ActionMoveRight, board.Width) {
+		actions = append(actions, tetris.ActionMoveRight)
+	}
+	if s.input.IsActionJustPressed(input.ActionRotateCW) {
+		actions = append(actions, tetris.ActionRotate)
+	}
+	if s.input.IsActionJustPressed(input.ActionRotateCCW) {
+		actions = append(actions, tetris.ActionRotateCCW)
+	}
+	for range s.input.SoftDrops(input.ActionSoftDrop, s.sim.State().GetGravity().G(), board.Height) {
+		actions = append(actions, tetris.ActionMoveDown)
 	}
+	if s.input.IsActionJustPressed(input.ActionHold) {
+		actions = append(actions, tetris.ActionHold)
+	}
+	if s.input.IsActionJustPressed(input.ActionHardDrop) {
+		actions = append(actions, tetris.ActionHardDrop)
+	}
+	return actions
+}
 
-	if s.state.IsGameOver() {
-		s.emitter.Emit(event.Event{Type: event.EventTypeGameOver, Payload: event.GameOverPayload{
-			Score: s.state.GetScore(),
-			Lines: s.state.GetLinesCleared(),
-			Level: s.state.GetLevel(),
-		}})
-		return nil
+// Abandon saves the replay of a game left before it ended, such as by quitting
+// from the pause menu, so every game played can be watched again.
+func (s *GameplayScene) Abandon() {
+	if s.finished {
+		return
 	}
+	s.finished = true
+	if r := s.recorder.Replay(); r.Frames > 0 {
+		s.replaySaver.SaveReplay(r)
+	}
+}
 
-	s.state.Update()
+// finish saves the replay and reports the result, once.
+func (s *GameplayScene) finish() {
+	if s.finished {
+		return
+	}
+	s.finished = true
+	s.replaySaver.SaveReplay(s.recorder.Replay())
 
-	return nil
+	state := s.sim.State()
+	s.emitter.Emit(event.Event{Type: event.EventTypeGameOver, Payload: gameevent.GameOverPayload{
+		Mode:      s.sim.Mode(),
+		Score:     state.GetScore(),
+		Lines:     state.GetLinesCleared(),
+		Level:     state.GetLevel(),
+		Reason:    state.GetGameOverReason(),
+		Complete:  s.sim.Complete(),
+		Ticks:     s.sim.Tick(),
+		LineTicks: s.sim.LineTicks(),
+		Pieces:    s.sim.Pieces(),
+	}})
 }
 
 func (s *GameplayScene) Draw(screen *ebiten.Image) {
-	screen.Fill(color.RGBA{R: 10, G: 10, B: 20, A: 255})
-
-	offsetX, offsetY := 4, 2
-	render.DrawBoard(screen, s.state.GetBoard(), offsetX, offsetY)
-	render.DrawPiece(screen, s.state.GetShadowPiece(), offsetX, offsetY)
-	render.DrawPiece(screen, s.state.GetCurrentPiece(), offsetX, offsetY)
+	render.DrawGameState(screen, s.sim.State())
+	switch s.sim.Mode() {
+	case sim.ModeSprint:
+		s.drawSprint(screen)
+	case sim.ModeUltra:
+		s.drawUltra(screen)
+	}
+	s.animator.Draw(screen)
+}
 
+// drawSprint adds the timer, the pace against the personal best and the split
+// times under the hold box.
+func (s *GameplayScene) drawSprint(screen *ebiten.Image) {
 	font := render.GetDefaultFont(render.FontMedium)
+	lineTicks := s.sim.LineTicks()
 
-	render.DrawText(screen, fmt.Sprintf("Score: %d", s.state.GetScore()), 1, 3, font)
-	render.DrawText(screen, fmt.Sprintf("Level: %d", s.state.GetLevel()), 1, 4, font)
-	render.DrawText(screen, fmt.Sprintf("Lines: %d", s.state.GetLinesCleared()), 1, 5, font)
+	render.DrawText(screen, "Time:", 0, 13, font)
+	render.DrawText(screen, render.FormatTime(s.sim.Elapsed()), 0, 14, font)
+	render.DrawText(screen, fmt.Sprintf("Left: %d", sim.SprintLines-len(lineTicks)), 0, 15, font)
 
-	render.DrawText(screen, "Next:", 16, 7, font)
-	render.DrawPiece(screen, s.state.GetNextPiece(), 12, 9)
+	if pace, ok := s.pace(); ok {
+		render.DrawText(screen, "Pace:", 0, 17, font)
+		render.DrawText(screen, render.FormatDelta(sim.Duration(pace)), 0, 18, font)
+	}
+
+	render.DrawText(screen, "Splits:", 0, 20, font)
+	bestSplits := sim.Splits(s.best)
+	for i, split := range sim.Splits(lineTicks) {
+		label := render.FormatTime(sim.Duration(split))
+		if i < len(bestSplits) {
+			label += " " + render.FormatDelta(sim.Duration(split-bestSplits[i]))
+		}
+		render.DrawText(screen, label, 0, 21+i, font)
+	}
+}
+
+// drawUltra adds the time left under the hold box.
+func (s *GameplayScene) drawUltra(screen *ebiten.Image) {
+	font := render.GetDefaultFont(render.FontMedium)
+	render.DrawText(screen, "Time Left:", 0, 13, font)
+	render.DrawText(screen, render.FormatTime(s.sim.Remaining()), 0, 14, font)
+}
+
+// pace returns how many ticks behind the personal best the last line was
+// cleared, negative when ahead of it.
+func (s *GameplayScene) pace() (int, bool) {
+	lines := len(s.sim.LineTicks())
+	if lines == 0 || lines > len(s.best) {
+		return 0, false
+	}
+	return s.sim.LineTicks()[lines-1] - s.best[lines-1], true
 }
 
 func (s *GameplayScene) OnEnter() {
-	s.state.Resume()
+	s.sim.Resume()
 }
 
 func (s *GameplayScene) OnExit() {
-	s.state.Pause()
+	s.sim.Pause()
 }
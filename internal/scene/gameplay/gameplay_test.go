@@ -0,0 +1,157 @@
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/replay"
+	"github.com/piotrowski/ebitris/internal/tetris"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmitter struct {
+	events []event.Event
+}
+
+func (e *recordingEmitter) Emit(ev event.Event) {
+	e.events = append(e.events, ev)
+}
+
+func (e *recordingEmitter) count(eventType event.EventType) int {
+	var n int
+	for _, ev := range e.events {
+		if ev.Type == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeReplaySaver struct {
+	saved []replay.Replay
+}
+
+func (s *fakeReplaySaver) SaveReplay(r replay.Replay) {
+	s.saved = append(s.saved, r)
+}
+
+func newTestScene() (*GameplayScene, *input.FakeSource, *recordingEmitter, *fakeReplaySaver) {
+	source := input.NewFakeSource()
+	emitter := &recordingEmitter{}
+	saver := &fakeReplaySaver{}
+	scene := NewStandardGameplayScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), saver)
+	scene.OnEnter()
+	return scene, source, emitter, saver
+}
+
+func TestRankedModesUseStandardBoard(t *testing.T) {
+	t.Parallel()
+
+	im := input.NewInputManager(input.NewFakeSource(), input.DefaultSettings(), input.DefaultBindings())
+	for _, scene := range []*GameplayScene{
+		NewSprintGameplayScene(&recordingEmitter{}, im, &fakeReplaySaver{}, nil),
+		NewUltraGameplayScene(&recordingEmitter{}, im, &fakeReplaySaver{}),
+	} {
+		board := scene.sim.State().GetBoard()
+		assert.Equal(t, 10, board.Width, scene.sim.Mode().String())
+		assert.Equal(t, 20, board.Height, scene.sim.Mode().String())
+	}
+}
+
+func TestGameplayPause(t *testing.T) {
+	t.Parallel()
+
+	scene, source, emitter, _ := newTestScene()
+
+	source.Frame(ebiten.KeyEscape)
+	require.NoError(t, scene.Update())
+	assert.Equal(t, 1, emitter.count(event.EventTypePause))
+	assert.Zero(t, scene.recorder.Replay().Frames, "the game does not run on the frame it pauses")
+}
+
+func TestGameplayHardDrop(t *testing.T) {
+	t.Parallel()
+
+	scene, source, emitter, _ := newTestScene()
+
+	source.Frame(ebiten.KeyLeft)
+	require.NoError(t, scene.Update())
+	assert.Equal(t, 1, emitter.count(event.EventTypeBlockMovedByPlayer))
+
+	source.Frame(ebiten.KeySpace)
+	require.NoError(t, scene.Update())
+	assert.Equal(t, 1, emitter.count(event.EventTypeBlockPlaced))
+	assert.Equal(t, 1, emitter.count(event.EventTypePieceLocked))
+	assert.Zero(t, emitter.count(event.EventTypeGameOver))
+
+	source.Frame(ebiten.KeySpace)
+	require.NoError(t, scene.Update())
+	assert.Equal(t, 1, emitter.count(event.EventTypeBlockPlaced), "holding the key drops only once")
+}
+
+func TestGameplayAbandonSavesReplay(t *testing.T) {
+	t.Parallel()
+
+	scene, source, emitter, saver := newTestScene()
+
+	source.Frame(ebiten.KeySpace)
+	require.NoError(t, scene.Update())
+	source.Frame()
+	require.NoError(t, scene.Update())
+
+	// Quitting from the pause menu leaves the game while it is paused
+	source.Frame(ebiten.KeyEscape)
+	require.NoError(t, scene.Update())
+	scene.OnExit()
+	assert.Empty(t, saver.saved, "pausing keeps the game going")
+
+	scene.Abandon()
+	require.Len(t, saver.saved, 1)
+	assert.Equal(t, 2, saver.saved[0].Frames)
+	assert.Len(t, saver.saved[0].Inputs, 1)
+
+	scene.Abandon()
+	assert.Len(t, saver.saved, 1, "the replay is saved once")
+	assert.Zero(t, emitter.count(event.EventTypeGameOver), "an abandoned game has no result")
+}
+
+func TestGameplayAbandonWithoutPlaying(t *testing.T) {
+	t.Parallel()
+
+	scene, _, _, saver := newTestScene()
+	scene.Abandon()
+	assert.Empty(t, saver.saved, "there is nothing to watch")
+}
+
+func TestGameplayGameOverSavesReplay(t *testing.T) {
+	t.Parallel()
+
+	scene, source, emitter, saver := newTestScene()
+
+	// Hard drop every other frame until the stack reaches the top
+	for frame := 0; frame < 1000 && emitter.count(event.EventTypeGameOver) == 0; frame++ {
+		if frame%2 == 0 {
+			source.Frame(ebiten.KeySpace)
+		} else {
+			source.Frame()
+		}
+		require.NoError(t, scene.Update())
+	}
+	require.Equal(t, 1, emitter.count(event.EventTypeGameOver))
+	assert.Positive(t, emitter.count(event.EventTypeDanger), "the stack passed the danger line on its way up")
+
+	source.Frame()
+	require.NoError(t, scene.Update())
+	assert.Len(t, saver.saved, 1, "the replay is saved once")
+	assert.Equal(t, 1, emitter.count(event.EventTypeGameOver), "the result is reported once")
+	assert.Equal(t, emitter.count(event.EventTypeBlockPlaced), len(saver.saved[0].Inputs))
+
+	last := emitter.events[len(emitter.events)-1]
+	require.Equal(t, event.EventTypeGameOver, last.Type)
+	assert.Equal(t, scene.sim.State().GetScore(), last.Payload.(gameevent.GameOverPayload).Score)
+	assert.NotEqual(t, tetris.GameOverNone, last.Payload.(gameevent.GameOverPayload).Reason)
+}
@@ -14,11 +14,11 @@ type MenuScene struct {
 	menu  *render.Menu
 }
 
-func NewMenuScene(emitter event.Emitter) *MenuScene {
+func NewMenuScene(emitter event.Emitter, im *input.InputManager) *MenuScene {
 	return &MenuScene{
 		emitter: emitter,
-		input:   input.NewInputManager(),
-		menu:    render.NewMenu([]string{"Start Game", "Scoreboard", "Exit"}),
+		input:   im,
+		menu:    render.NewMenu([]string{"Start Game", "Scoreboard", "Watch Replay", "Controls", "Options", "Exit"}),
 	}
 }
 
@@ -26,10 +26,16 @@ func (s *MenuScene) Update() error {
 	if s.menu.HandleInput(s.input) {
 		switch s.menu.Selected() {
 		case 0:
-			s.emitter.Emit(event.Event{Type: event.EventTypeStartGame})
+			s.emitter.Emit(event.Event{Type: event.EventTypeModeSelect})
 		case 1:
 			s.emitter.Emit(event.Event{Type: event.EventTypeScoreboard})
 		case 2:
+			s.emitter.Emit(event.Event{Type: event.EventTypeWatchReplay})
+		case 3:
+			s.emitter.Emit(event.Event{Type: event.EventTypeControls})
+		case 4:
+			s.emitter.Emit(event.Event{Type: event.EventTypeOptions})
+		case 5:
 			s.emitter.Emit(event.Event{Type: event.EventTypeQuit})
 		}
 	}
@@ -4,15 +4,30 @@ import (
 	"log/slog"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
 	"github.com/piotrowski/ebitris/internal/pkg/audio"
 	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/replay"
 	"github.com/piotrowski/ebitris/internal/pkg/scene"
 	"github.com/piotrowski/ebitris/internal/pkg/score"
+	"github.com/piotrowski/ebitris/internal/pkg/settings"
+	"github.com/piotrowski/ebitris/internal/scene/controls"
 	"github.com/piotrowski/ebitris/internal/scene/gameover"
 	"github.com/piotrowski/ebitris/internal/scene/gameplay"
 	menu "github.com/piotrowski/ebitris/internal/scene/mainmenu"
+	"github.com/piotrowski/ebitris/internal/scene/modeselect"
+	"github.com/piotrowski/ebitris/internal/scene/options"
 	"github.com/piotrowski/ebitris/internal/scene/pause"
+	replayscene "github.com/piotrowski/ebitris/internal/scene/replay"
 	"github.com/piotrowski/ebitris/internal/scene/scoreboard"
+	"github.com/piotrowski/ebitris/internal/sim"
+)
+
+// Size of the layout the scenes draw to, before the window scales it.
+const (
+	ScreenWidth  = 600
+	ScreenHeight = 800
 )
 
 type eventManager interface {
@@ -26,30 +41,68 @@ type scoreManager interface {
 	score.Saver
 }
 
+type sprintManager interface {
+	score.SprintGetter
+	score.SprintSaver
+}
+
+type replayManager interface {
+	replay.Saver
+	replay.Loader
+}
+
+type bindingsManager interface {
+	input.BindingsLoader
+	input.BindingsSaver
+}
+
+type settingsManager interface {
+	settings.Loader
+	settings.Saver
+}
+
 type audioManager interface {
 	audio.EffectPlayer
 	audio.MusicPlayer
 	audio.AudioUpdater
+	audio.VolumeController
 }
 
 type Manager struct {
-	events       eventManager
-	sceneManager scene.Manager
-	scoreManager scoreManager
-	audioManager audioManager
+	events          eventManager
+	sceneManager    scene.Manager
+	scoreManager    scoreManager
+	sprintManager   sprintManager
+	ultraManager    scoreManager
+	replayManager   replayManager
+	bindingsManager bindingsManager
+	settingsManager settingsManager
+	audioManager    audioManager
+	input           *input.InputManager
+	settings        settings.Settings
+	mode            sim.Mode                // Mode of the last game started, played again on restart
+	game            *gameplay.GameplayScene // Game being played, nil when there is none
 }
 
 func NewManager() *Manager {
 	m := &Manager{
-		events:       event.NewEventManager(),
-		sceneManager: scene.NewSceneManager(),
-		scoreManager: score.NewScoreManager(),
-		audioManager: audio.NewAudioManager(),
+		events:          event.NewEventManager(),
+		sceneManager:    scene.NewSceneManager(),
+		scoreManager:    score.NewScoreManager(),
+		sprintManager:   score.NewSprintManager(),
+		ultraManager:    score.NewUltraScoreManager(),
+		replayManager:   replay.NewStore(),
+		bindingsManager: input.NewBindingsStore(),
+		settingsManager: settings.NewStore(),
+		audioManager:    audio.NewAudioManager(),
 	}
+	m.input = input.NewInputManager(input.NewEbitenSource(), input.DefaultSettings(), m.bindingsManager.LoadBindings())
+	m.applySettings(m.settingsManager.LoadSettings())
 
 	m.subscribeNavigation()
 	m.subscribeMusic()
 	m.subscribeEffects()
+	m.subscribeSettings()
 
 	m.events.Emit(event.Event{Type: event.EventTypeMainMenu})
 
@@ -61,46 +114,129 @@ func (m *Manager) subscribeNavigation() {
 		m.sceneManager.SwitchBack()
 	})
 
+	m.events.Subscribe(event.EventTypeModeSelect, func(e event.Event) {
+		m.sceneManager.SwitchTo(modeselect.NewModeSelectScene(m.events, m.input))
+	})
+
 	m.events.Subscribe(event.EventTypeStartGame, func(e event.Event) {
-		m.sceneManager.SwitchTo(gameplay.NewStandardGameplayScene(m.events))
+		if start, isOk := e.Payload.(gameevent.StartGamePayload); isOk {
+			m.mode = start.Mode
+		}
+
+		m.abandonGame()
+		board := m.settings.Gameplay
+		switch m.mode {
+		case sim.ModeSprint:
+			m.game = gameplay.NewSprintGameplayScene(m.events, m.input, m.replayManager, m.bestSprint())
+		case sim.ModeUltra:
+			m.game = gameplay.NewUltraGameplayScene(m.events, m.input, m.replayManager)
+		default:
+			m.game = gameplay.NewGameplayScene(m.events, m.input, m.replayManager, board.BoardWidth, board.BoardHeight, board.PreviewCount)
+		}
+		m.sceneManager.SwitchTo(m.game)
 	})
 
 	m.events.Subscribe(event.EventTypeMainMenu, func(e event.Event) {
-		m.sceneManager.SwitchTo(menu.NewMenuScene(m.events))
+		m.abandonGame()
+		m.sceneManager.SwitchTo(menu.NewMenuScene(m.events, m.input))
 	})
 
 	m.events.Subscribe(event.EventTypeScoreboard, func(e event.Event) {
-		m.sceneManager.SwitchTo(scoreboard.NewScoreboardScene(m.events, m.scoreManager))
+		m.sceneManager.SwitchTo(scoreboard.NewScoreboardScene(m.events, m.input, m.scoreManager, m.sprintManager, m.ultraManager))
+	})
+
+	m.events.Subscribe(event.EventTypeControls, func(e event.Event) {
+		m.sceneManager.SwitchTo(controls.NewControlsScene(m.events, m.input, m.bindingsManager))
+	})
+
+	m.events.Subscribe(event.EventTypeOptions, func(e event.Event) {
+		m.sceneManager.SwitchTo(options.NewOptionsScene(m.events, m.input, m.settings, m.settingsManager))
+	})
+
+	m.events.Subscribe(event.EventTypeWatchReplay, func(e event.Event) {
+		latest, err := m.replayManager.LoadLatest()
+		if err != nil {
+			slog.Warn("failed to load replay", "subsystem", "scene", "err", err)
+			return
+		}
+		player, err := replay.NewPlayer(latest)
+		if err != nil {
+			slog.Warn("failed to start replay", "subsystem", "scene", "err", err)
+			return
+		}
+		m.sceneManager.SwitchTo(replayscene.NewReplayScene(m.events, m.input, player))
 	})
 
 	m.events.Subscribe(event.EventTypePause, func(e event.Event) {
-		m.sceneManager.SwitchTo(pause.NewPauseScene(m.events))
+		m.sceneManager.SwitchTo(pause.NewPauseScene(m.events, m.input))
 	})
 
 	m.events.Subscribe(event.EventTypeGameOver, func(e event.Event) {
-		endScore, isOk := e.Payload.(event.GameOverPayload)
+		endScore, isOk := e.Payload.(gameevent.GameOverPayload)
 		if !isOk {
 			slog.Warn("unexpected GameOverPayload", "subsystem", "scene")
 		}
-		m.sceneManager.SwitchTo(gameover.NewGameOverScene(m.events, m.scoreManager, endScore.Score, endScore.Level, endScore.Lines))
+		switch endScore.Mode {
+		case sim.ModeSprint:
+			m.sceneManager.SwitchTo(gameover.NewSprintGameOverScene(m.events, m.input, m.sprintManager, endScore, m.bestSprint()))
+		case sim.ModeUltra:
+			m.sceneManager.SwitchTo(gameover.NewUltraGameOverScene(m.events, m.input, m.ultraManager, endScore))
+		default:
+			m.sceneManager.SwitchTo(gameover.NewGameOverScene(m.events, m.input, m.scoreManager, endScore.Score, endScore.Level, endScore.Lines))
+		}
 	})
 
 	m.events.Subscribe(event.EventTypeQuit, func(e event.Event) {
+		m.abandonGame()
 		m.sceneManager.Quit()
 	})
 }
 
+// abandonGame saves the replay of a game that is being left before it ended.
+// Games that did end have saved theirs already.
+func (m *Manager) abandonGame() {
+	if m.game != nil {
+		m.game.Abandon()
+		m.game = nil
+	}
+}
+
+// bestSprint returns the line times of the fastest sprint, or nil before one is finished.
+func (m *Manager) bestSprint() []int {
+	best, isOk := m.sprintManager.PersonalBest()
+	if !isOk {
+		return nil
+	}
+	return best.LineTicks
+}
+
 func (m *Manager) subscribeMusic() {
 	m.events.Subscribe(event.EventTypeStartGame, func(e event.Event) {
-		m.audioManager.StartPlaylist(audio.ArcadeBeat, audio.ReturnOfThe8BitEra)
+		m.audioManager.PlayPlaylist(audio.GameplayPlaylist)
+		m.audioManager.SetDanger(false)
+		m.audioManager.SetTempo(audio.LevelTempo(1))
 	})
 
 	m.events.Subscribe(event.EventTypeMainMenu, func(e event.Event) {
-		m.audioManager.StartPlaylist(audio.ArcadeBeat, audio.ReturnOfThe8BitEra)
+		m.audioManager.PlayPlaylist(audio.MenuPlaylist)
+		m.audioManager.SetTempo(1)
 	})
 
 	m.events.Subscribe(event.EventTypeGameOver, func(e event.Event) {
-		m.audioManager.StartPlaylist(audio.ArcadeBeat, audio.ReturnOfThe8BitEra)
+		m.audioManager.PlayPlaylist(audio.GameOverPlaylist)
+		m.audioManager.SetTempo(1)
+	})
+
+	m.events.Subscribe(event.EventTypeLevelUp, func(e event.Event) {
+		if levelUp, isOk := e.Payload.(gameevent.LevelUpPayload); isOk {
+			m.audioManager.SetTempo(audio.LevelTempo(levelUp.Level))
+		}
+	})
+
+	m.events.Subscribe(event.EventTypeDanger, func(e event.Event) {
+		if danger, isOk := e.Payload.(gameevent.DangerPayload); isOk {
+			m.audioManager.SetDanger(danger.Danger)
+		}
 	})
 }
 
@@ -114,7 +250,39 @@ func (m *Manager) subscribeEffects() {
 	})
 }
 
+func (m *Manager) subscribeSettings() {
+	m.events.Subscribe(event.EventTypeSettingsChanged, func(e event.Event) {
+		changed, isOk := e.Payload.(gameevent.SettingsChangedPayload)
+		if !isOk {
+			slog.Warn("unexpected SettingsChangedPayload", "subsystem", "scene")
+			return
+		}
+		m.applySettings(changed.Settings)
+	})
+}
+
+// applySettings puts the settings into effect. Board size is read when the next game starts.
+func (m *Manager) applySettings(s settings.Settings) {
+	m.settings = s
+
+	m.input.SetSettings(input.Settings{
+		DAS:            s.Controls.DAS,
+		ARR:            s.Controls.ARR,
+		SoftDropFactor: s.Controls.SoftDropFactor,
+		KeepDASCharge:  s.Controls.KeepDASCharge,
+	})
+	m.audioManager.SetMasterVolume(s.Audio.MasterVolume)
+	m.audioManager.SetMusicVolume(s.Audio.MusicVolume)
+	m.audioManager.SetEffectsVolume(s.Audio.EffectsVolume)
+	m.audioManager.SetMuted(s.Audio.Muted)
+
+	ebiten.SetWindowSize(ScreenWidth*s.Display.WindowScale/100, ScreenHeight*s.Display.WindowScale/100)
+	ebiten.SetFullscreen(s.Display.Fullscreen)
+}
+
 func (m *Manager) Update() error {
+	m.input.Update()
+
 	err := m.sceneManager.Update()
 	if err != nil {
 		return err
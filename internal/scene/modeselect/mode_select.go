@@ -0,0 +1,55 @@
+package modeselect
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/render"
+	"github.com/piotrowski/ebitris/internal/sim"
+)
+
+// modes are the modes offered, in the order of the menu items.
+var modes = []sim.Mode{sim.ModeMarathon, sim.ModeSprint, sim.ModeUltra}
+
+// ModeSelectScene picks the mode of the next game before it starts.
+type ModeSelectScene struct {
+	emitter event.Emitter
+	input   *input.InputManager
+	menu    *render.Menu
+}
+
+func NewModeSelectScene(emitter event.Emitter, im *input.InputManager) *ModeSelectScene {
+	return &ModeSelectScene{
+		emitter: emitter,
+		input:   im,
+		menu:    render.NewMenu([]string{"Marathon", "Sprint 40L", "Ultra 3:00", "Back"}),
+	}
+}
+
+func (s *ModeSelectScene) Update() error {
+	if s.input.IsActionJustPressed(input.ActionBack) {
+		s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
+		return nil
+	}
+
+	if s.menu.HandleInput(s.input) {
+		selected := s.menu.Selected()
+		if selected == len(modes) {
+			s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
+			return nil
+		}
+		s.emitter.Emit(event.Event{Type: event.EventTypeStartGame, Payload: gameevent.StartGamePayload{Mode: modes[selected]}})
+	}
+
+	return nil
+}
+
+func (s *ModeSelectScene) Draw(screen *ebiten.Image) {
+	fontLarge := render.GetDefaultFont(render.FontLarge)
+	render.DrawText(screen, "Select Mode", 5, 5, fontLarge)
+	s.menu.Draw(screen, 5, 10)
+}
+
+func (s *ModeSelectScene) OnEnter() {}
+func (s *ModeSelectScene) OnExit()  {}
@@ -0,0 +1,190 @@
+package options
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/settings"
+	"github.com/piotrowski/ebitris/internal/render"
+)
+
+// option is one editable line of the options menu.
+type option struct {
+	label  string
+	value  func(s settings.Settings) string
+	adjust func(s *settings.Settings, step int) // step is -1 or +1
+}
+
+var options = []option{
+	{
+		label:  "Master Volume",
+		value:  func(s settings.Settings) string { return percent(s.Audio.MasterVolume) },
+		adjust: func(s *settings.Settings, step int) { s.Audio.MasterVolume = stepVolume(s.Audio.MasterVolume, step) },
+	},
+	{
+		label:  "Music Volume",
+		value:  func(s settings.Settings) string { return percent(s.Audio.MusicVolume) },
+		adjust: func(s *settings.Settings, step int) { s.Audio.MusicVolume = stepVolume(s.Audio.MusicVolume, step) },
+	},
+	{
+		label:  "Effects Volume",
+		value:  func(s settings.Settings) string { return percent(s.Audio.EffectsVolume) },
+		adjust: func(s *settings.Settings, step int) { s.Audio.EffectsVolume = stepVolume(s.Audio.EffectsVolume, step) },
+	},
+	{
+		label:  "Mute",
+		value:  func(s settings.Settings) string { return onOff(s.Audio.Muted) },
+		adjust: func(s *settings.Settings, step int) { s.Audio.Muted = !s.Audio.Muted },
+	},
+	{
+		label:  "DAS",
+		value:  func(s settings.Settings) string { return fmt.Sprintf("%d frames", s.Controls.DAS) },
+		adjust: func(s *settings.Settings, step int) { s.Controls.DAS += step },
+	},
+	{
+		label:  "ARR",
+		value:  func(s settings.Settings) string { return fmt.Sprintf("%d frames", s.Controls.ARR) },
+		adjust: func(s *settings.Settings, step int) { s.Controls.ARR += step },
+	},
+	{
+		label:  "Soft Drop Speed",
+		value:  func(s settings.Settings) string { return fmt.Sprintf("%dx", s.Controls.SoftDropFactor) },
+		adjust: func(s *settings.Settings, step int) { s.Controls.SoftDropFactor += step },
+	},
+	{
+		label:  "Keep DAS Charge",
+		value:  func(s settings.Settings) string { return onOff(s.Controls.KeepDASCharge) },
+		adjust: func(s *settings.Settings, step int) { s.Controls.KeepDASCharge = !s.Controls.KeepDASCharge },
+	},
+	{
+		label:  "Board Width",
+		value:  func(s settings.Settings) string { return fmt.Sprint(s.Gameplay.BoardWidth) },
+		adjust: func(s *settings.Settings, step int) { s.Gameplay.BoardWidth += step },
+	},
+	{
+		label:  "Board Height",
+		value:  func(s settings.Settings) string { return fmt.Sprint(s.Gameplay.BoardHeight) },
+		adjust: func(s *settings.Settings, step int) { s.Gameplay.BoardHeight += step },
+	},
+	{
+		label:  "Next Pieces",
+		value:  func(s settings.Settings) string { return fmt.Sprint(s.Gameplay.PreviewCount) },
+		adjust: func(s *settings.Settings, step int) { s.Gameplay.PreviewCount += step },
+	},
+	{
+		label:  "Window Size",
+		value:  func(s settings.Settings) string { return fmt.Sprintf("%d%%", s.Display.WindowScale) },
+		adjust: func(s *settings.Settings, step int) { s.Display.WindowScale += 25 * step },
+	},
+	{
+		label:  "Fullscreen",
+		value:  func(s settings.Settings) string { return onOff(s.Display.Fullscreen) },
+		adjust: func(s *settings.Settings, step int) { s.Display.Fullscreen = !s.Display.Fullscreen },
+	},
+}
+
+// OptionsScene edits the settings. Every change is applied right away through
+// a SettingsChanged event, and the settings are saved when leaving.
+type OptionsScene struct {
+	emitter       event.Emitter
+	input         *input.InputManager
+	settingsSaver settings.Saver
+	menu          *render.Menu
+
+	settings settings.Settings
+}
+
+func NewOptionsScene(emitter event.Emitter, im *input.InputManager, current settings.Settings, settingsSaver settings.Saver) *OptionsScene {
+	s := &OptionsScene{
+		emitter:       emitter,
+		input:         im,
+		settingsSaver: settingsSaver,
+		settings:      current,
+	}
+	s.menu = render.NewMenu(s.labels())
+	return s
+}
+
+func (s *OptionsScene) Update() error {
+	if s.input.IsActionJustPressed(input.ActionBack) {
+		s.leave()
+		return nil
+	}
+
+	selected := s.menu.Selected()
+	if selected < len(options) {
+		switch {
+		case s.input.ShouldRepeat(input.ActionMoveLeft):
+			s.change(func(cfg *settings.Settings) { options[selected].adjust(cfg, -1) })
+		case s.input.ShouldRepeat(input.ActionMoveRight):
+			s.change(func(cfg *settings.Settings) { options[selected].adjust(cfg, 1) })
+		}
+	}
+
+	if s.menu.HandleInput(s.input) {
+		switch selected := s.menu.Selected(); {
+		case selected < len(options):
+			s.change(func(cfg *settings.Settings) { options[selected].adjust(cfg, 1) })
+		case selected == len(options):
+			s.change(func(cfg *settings.Settings) { *cfg = settings.Default() })
+		default:
+			s.leave()
+		}
+	}
+
+	return nil
+}
+
+// change edits the settings and applies them.
+func (s *OptionsScene) change(edit func(cfg *settings.Settings)) {
+	edit(&s.settings)
+	s.settings = s.settings.Clamped()
+	s.menu.SetItems(s.labels())
+	s.emitter.Emit(event.Event{Type: event.EventTypeSettingsChanged, Payload: gameevent.SettingsChangedPayload{Settings: s.settings}})
+}
+
+func (s *OptionsScene) leave() {
+	s.settingsSaver.SaveSettings(s.settings)
+	s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
+}
+
+func (s *OptionsScene) labels() []string {
+	labels := make([]string, 0, len(options)+2)
+	for _, option := range options {
+		labels = append(labels, fmt.Sprintf("%s: %s", option.label, option.value(s.settings)))
+	}
+	return append(labels, "Reset to Defaults", "Back")
+}
+
+func (s *OptionsScene) Draw(screen *ebiten.Image) {
+	fontLarge := render.GetDefaultFont(render.FontLarge)
+	fontMedium := render.GetDefaultFont(render.FontMedium)
+
+	render.DrawText(screen, "Options", 5, 3, fontLarge)
+	s.menu.Draw(screen, 5, 6)
+	render.DrawText(screen, "LEFT/RIGHT to change, ESC to save and exit", 2, 22, fontMedium)
+	render.DrawText(screen, "Board size and next pieces apply from the next Marathon game", 2, 23, fontMedium)
+}
+
+func (s *OptionsScene) OnEnter() {}
+func (s *OptionsScene) OnExit()  {}
+
+// stepVolume moves a volume by 10%, rounded so repeated steps land on whole percents.
+func stepVolume(volume float64, step int) float64 {
+	return math.Round(volume*10+float64(step)) / 10
+}
+
+func percent(volume float64) string {
+	return fmt.Sprintf("%d%%", int(math.Round(volume*100)))
+}
+
+func onOff(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
@@ -0,0 +1,116 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmitter struct {
+	events []event.Event
+}
+
+func (e *recordingEmitter) Emit(ev event.Event) {
+	e.events = append(e.events, ev)
+}
+
+// applied returns the settings from the last SettingsChanged event.
+func (e *recordingEmitter) applied(t *testing.T) settings.Settings {
+	t.Helper()
+
+	for i := len(e.events) - 1; i >= 0; i-- {
+		if payload, ok := e.events[i].Payload.(gameevent.SettingsChangedPayload); ok {
+			return payload.Settings
+		}
+	}
+	require.Fail(t, "no settings were applied")
+	return settings.Settings{}
+}
+
+type fakeSettingsSaver struct {
+	saved []settings.Settings
+}
+
+func (s *fakeSettingsSaver) SaveSettings(settings settings.Settings) {
+	s.saved = append(s.saved, settings)
+}
+
+// press plays one frame with the keys held, then one with them released.
+func press(t *testing.T, scene *OptionsScene, source *input.FakeSource, keys ...ebiten.Key) {
+	t.Helper()
+
+	source.Frame(keys...)
+	require.NoError(t, scene.Update())
+	source.Frame()
+	require.NoError(t, scene.Update())
+}
+
+func TestOptionsApplyLive(t *testing.T) {
+	t.Parallel()
+
+	source := input.NewFakeSource()
+	emitter := &recordingEmitter{}
+	saver := &fakeSettingsSaver{}
+	scene := NewOptionsScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), settings.Default(), saver)
+
+	press(t, scene, source, ebiten.KeyDown) // Music Volume
+	press(t, scene, source, ebiten.KeyRight)
+	press(t, scene, source, ebiten.KeyRight)
+	assert.InDelta(t, 0.3, emitter.applied(t).Audio.MusicVolume, 1e-9)
+
+	press(t, scene, source, ebiten.KeyDown) // Effects Volume
+	press(t, scene, source, ebiten.KeyDown) // Mute
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.True(t, emitter.applied(t).Audio.Muted)
+
+	for range 4 {
+		press(t, scene, source, ebiten.KeyDown)
+	}
+	press(t, scene, source, ebiten.KeyEnter) // Keep DAS Charge
+	assert.False(t, emitter.applied(t).Controls.KeepDASCharge)
+
+	press(t, scene, source, ebiten.KeyDown) // Board Width
+	for range 10 {
+		press(t, scene, source, ebiten.KeyRight)
+	}
+	assert.Equal(t, settings.MaxBoardWidth, emitter.applied(t).Gameplay.BoardWidth, "values stay in range")
+
+	press(t, scene, source, ebiten.KeyDown)
+	press(t, scene, source, ebiten.KeyDown) // Next Pieces
+	press(t, scene, source, ebiten.KeyLeft)
+	assert.Equal(t, 4, emitter.applied(t).Gameplay.PreviewCount)
+	assert.Empty(t, saver.saved, "settings are saved when leaving")
+
+	press(t, scene, source, ebiten.KeyEscape)
+	require.Len(t, saver.saved, 1)
+	assert.Equal(t, emitter.applied(t), saver.saved[0])
+	assert.Equal(t, event.EventTypeMainMenu, emitter.events[len(emitter.events)-1].Type)
+}
+
+func TestOptionsReset(t *testing.T) {
+	t.Parallel()
+
+	current := settings.Default()
+	current.Display.Fullscreen = true
+	current.Audio.EffectsVolume = 0
+
+	source := input.NewFakeSource()
+	emitter := &recordingEmitter{}
+	saver := &fakeSettingsSaver{}
+	scene := NewOptionsScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), current, saver)
+
+	press(t, scene, source, ebiten.KeyUp) // Back
+	press(t, scene, source, ebiten.KeyUp) // Reset to Defaults
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, settings.Default(), emitter.applied(t))
+
+	press(t, scene, source, ebiten.KeyDown)
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, []settings.Settings{settings.Default()}, saver.saved)
+}
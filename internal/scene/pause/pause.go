@@ -13,16 +13,16 @@ type PauseScene struct {
 	menu    *render.Menu
 }
 
-func NewPauseScene(emitter event.Emitter) *PauseScene {
+func NewPauseScene(emitter event.Emitter, im *input.InputManager) *PauseScene {
 	return &PauseScene{
 		emitter: emitter,
-		input:   input.NewInputManager(),
+		input:   im,
 		menu:    render.NewMenu([]string{"Resume", "Restart", "Main Menu"}),
 	}
 }
 
 func (s *PauseScene) Update() error {
-	if s.input.IsKeyJustPressed(ebiten.KeyEscape) {
+	if s.input.IsActionJustPressed(input.ActionBack) || s.input.IsActionJustPressed(input.ActionPause) {
 		s.emitter.Emit(event.Event{Type: event.EventTypeGoBack})
 	}
 
@@ -0,0 +1,96 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/gameevent"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/replay"
+	"github.com/piotrowski/ebitris/internal/render"
+)
+
+// fastForwardSpeed is how many recorded frames play per tick while fast-forwarding.
+const fastForwardSpeed = 4
+
+type ReplayScene struct {
+	emitter  event.Emitter
+	input    *input.InputManager
+	player   *replay.Player
+	animator *render.Animator
+
+	paused bool
+}
+
+func NewReplayScene(emitter event.Emitter, im *input.InputManager, player *replay.Player) *ReplayScene {
+	return &ReplayScene{
+		emitter:  emitter,
+		input:    im,
+		player:   player,
+		animator: render.NewAnimator(player.State().GetBoard().Width),
+	}
+}
+
+func (s *ReplayScene) Update() error {
+	if s.input.IsActionJustPressed(input.ActionBack) {
+		s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
+		return nil
+	}
+
+	if s.input.IsKeyJustPressed(ebiten.KeySpace) || s.input.IsActionJustPressed(input.ActionPause) {
+		s.paused = !s.paused
+	}
+
+	switch {
+	case s.paused:
+		// Step one frame at a time while paused
+		if s.input.ShouldRepeat(input.ActionMoveRight) {
+			s.step()
+		}
+	case s.input.IsKeyPressed(ebiten.KeyF) || s.input.IsActionPressed(input.ActionSoftDrop):
+		for range fastForwardSpeed {
+			s.step()
+		}
+	default:
+		s.step()
+	}
+
+	return nil
+}
+
+// step plays one recorded frame and keeps the animations in time with it.
+func (s *ReplayScene) step() {
+	if s.player.Done() {
+		return
+	}
+
+	result := s.player.Step()
+	s.animator.Update()
+	for _, lock := range result.Locks {
+		for _, e := range gameevent.LockEvents(lock) {
+			s.animator.HandleEvent(e)
+		}
+	}
+}
+
+func (s *ReplayScene) Draw(screen *ebiten.Image) {
+	render.DrawGameState(screen, s.player.State())
+	s.animator.Draw(screen)
+
+	font := render.GetDefaultFont(render.FontMedium)
+	render.DrawText(screen, "REPLAY", 1, 1, font)
+	render.DrawText(screen, fmt.Sprintf("Frame %d/%d", s.player.Frame(), s.player.TotalFrames()), 1, 23, font)
+
+	switch {
+	case s.player.Done():
+		render.DrawText(screen, "Finished - ESC to exit", 1, 24, font)
+	case s.paused:
+		render.DrawText(screen, "Paused - RIGHT to step, SPACE to resume", 1, 24, font)
+	default:
+		render.DrawText(screen, "SPACE pause, hold F fast-forward, ESC exit", 1, 24, font)
+	}
+}
+
+func (s *ReplayScene) OnEnter() {}
+func (s *ReplayScene) OnExit()  {}
@@ -8,47 +8,83 @@ import (
 	"github.com/piotrowski/ebitris/internal/pkg/input"
 	"github.com/piotrowski/ebitris/internal/pkg/score"
 	"github.com/piotrowski/ebitris/internal/render"
+	"github.com/piotrowski/ebitris/internal/sim"
 )
 
 var pageSize = 10
 
+// boards are the leaderboards shown, in the order left and right step through them.
+var boards = []sim.Mode{sim.ModeMarathon, sim.ModeSprint, sim.ModeUltra}
+
 type ScoreboardScene struct {
 	emitter event.Emitter
 	input   *input.InputManager
 	menu    *render.Menu
 
-	scoreGetter score.Getter
+	scoreGetter  score.Getter
+	sprintGetter score.SprintGetter
+	ultraGetter  score.Getter
 
+	board        int // Index into boards of the leaderboard shown
 	currentPage  int
 	hasMorePages bool
-	scores       []score.ScoreEntry
+	scores       []score.ScoreEntry // Marathon or Ultra scores, whichever board is shown
+	sprints      []score.SprintEntry
 }
 
-func NewScoreboardScene(emitter event.Emitter, scoreGetter score.Getter) *ScoreboardScene {
+func NewScoreboardScene(emitter event.Emitter, im *input.InputManager, scoreGetter score.Getter, sprintGetter score.SprintGetter, ultraGetter score.Getter) *ScoreboardScene {
 	s := &ScoreboardScene{
-		emitter:     emitter,
-		scoreGetter: scoreGetter,
-		input:       input.NewInputManager(),
-		menu:        render.NewMenu([]string{"Next Page", "Previous Page", "Back"}),
+		emitter:      emitter,
+		scoreGetter:  scoreGetter,
+		sprintGetter: sprintGetter,
+		ultraGetter:  ultraGetter,
+		input:        im,
+		menu:         render.NewMenu([]string{"Next Page", "Previous Page", "Back"}),
 	}
 
-	s.scores, s.hasMorePages = s.scoreGetter.GetPage(s.currentPage, pageSize)
+	s.loadPage()
 	return s
 }
 
+// switchBoard moves by step through the leaderboards and shows the first page.
+func (s *ScoreboardScene) switchBoard(step int) {
+	s.board = (s.board + step) % len(boards)
+	s.currentPage = 0
+	s.loadPage()
+}
+
+// loadPage fetches the current page of the leaderboard being shown.
+func (s *ScoreboardScene) loadPage() {
+	switch boards[s.board] {
+	case sim.ModeMarathon:
+		s.scores, s.hasMorePages = s.scoreGetter.GetPage(s.currentPage, pageSize)
+	case sim.ModeSprint:
+		s.sprints, s.hasMorePages = s.sprintGetter.GetSprintPage(s.currentPage, pageSize)
+	case sim.ModeUltra:
+		s.scores, s.hasMorePages = s.ultraGetter.GetPage(s.currentPage, pageSize)
+	}
+}
+
 func (s *ScoreboardScene) Update() error {
+	switch {
+	case s.input.IsActionJustPressed(input.ActionMoveLeft):
+		s.switchBoard(len(boards) - 1)
+	case s.input.IsActionJustPressed(input.ActionMoveRight):
+		s.switchBoard(1)
+	}
+
 	if s.menu.HandleInput(s.input) {
 		switch s.menu.Selected() {
 		case 0:
 			if s.hasMorePages {
 				s.currentPage++
-				s.scores, s.hasMorePages = s.scoreGetter.GetPage(s.currentPage, pageSize)
+				s.loadPage()
 			}
 		case 1:
 			if s.currentPage > 0 {
 				s.currentPage--
 			}
-			s.scores, s.hasMorePages = s.scoreGetter.GetPage(s.currentPage, pageSize)
+			s.loadPage()
 		case 2:
 			s.emitter.Emit(event.Event{Type: event.EventTypeMainMenu})
 		}
@@ -61,16 +97,23 @@ func (s *ScoreboardScene) Draw(screen *ebiten.Image) {
 	fontLarge := render.GetDefaultFont(render.FontLarge)
 	fontMedium := render.GetDefaultFont(render.FontMedium)
 	render.DrawText(screen, "Ebitris", 5, 5, fontLarge)
+	render.DrawText(screen, fmt.Sprintf("< %s >", boards[s.board]), 5, 7, fontMedium)
 	s.menu.Draw(screen, 5, 10)
 
-	for i, score := range s.scores {
-		render.DrawText(
-			screen,
-			fmt.Sprintf("%d. %s - Score: %d, Level: %d, Lines: %d", s.currentPage*pageSize+i+1, score.Initials, score.Score, score.Level, score.Lines),
-			5,
-			15+i,
-			fontMedium,
-		)
+	var rows []string
+	switch boards[s.board] {
+	case sim.ModeMarathon, sim.ModeUltra:
+		for _, score := range s.scores {
+			rows = append(rows, fmt.Sprintf("%s - Score: %d, Level: %d, Lines: %d", score.Initials, score.Score, score.Level, score.Lines))
+		}
+	case sim.ModeSprint:
+		for _, sprint := range s.sprints {
+			rows = append(rows, fmt.Sprintf("%s - %s, %d pieces", sprint.Initials, render.FormatTime(sim.Duration(sprint.Ticks)), sprint.Pieces))
+		}
+	}
+
+	for i, row := range rows {
+		render.DrawText(screen, fmt.Sprintf("%d. %s", s.currentPage*pageSize+i+1, row), 5, 15+i, fontMedium)
 	}
 }
 
@@ -0,0 +1,137 @@
+package scoreboard
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/piotrowski/ebitris/internal/pkg/event"
+	"github.com/piotrowski/ebitris/internal/pkg/input"
+	"github.com/piotrowski/ebitris/internal/pkg/score"
+	"github.com/piotrowski/ebitris/internal/sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmitter struct {
+	events []event.EventType
+}
+
+func (e *recordingEmitter) Emit(ev event.Event) {
+	e.events = append(e.events, ev.Type)
+}
+
+// fakeScores serves count entries whose score is their position on the board.
+type fakeScores struct {
+	count int
+	pages []int
+}
+
+func (f *fakeScores) GetPage(page, size int) ([]score.ScoreEntry, bool) {
+	f.pages = append(f.pages, page)
+
+	var entries []score.ScoreEntry
+	for i := page * size; i < min((page+1)*size, f.count); i++ {
+		entries = append(entries, score.ScoreEntry{Initials: "AAA", Score: i + 1})
+	}
+	return entries, (page+1)*size < f.count
+}
+
+// fakeSprints serves sprints a second apart, the fastest first.
+type fakeSprints struct {
+	count int
+}
+
+func (f *fakeSprints) GetSprintPage(page, size int) ([]score.SprintEntry, bool) {
+	var entries []score.SprintEntry
+	for i := page * size; i < min((page+1)*size, f.count); i++ {
+		entries = append(entries, score.SprintEntry{Initials: "BBB", Ticks: (i + 1) * sim.TickRate})
+	}
+	return entries, (page+1)*size < f.count
+}
+
+func (f *fakeSprints) PersonalBest() (score.SprintEntry, bool) {
+	entries, _ := f.GetSprintPage(0, 1)
+	if len(entries) == 0 {
+		return score.SprintEntry{}, false
+	}
+	return entries[0], true
+}
+
+// press plays one frame with the keys held, then one with them released.
+func press(t *testing.T, scene *ScoreboardScene, source *input.FakeSource, keys ...ebiten.Key) {
+	t.Helper()
+
+	source.Frame(keys...)
+	require.NoError(t, scene.Update())
+	source.Frame()
+	require.NoError(t, scene.Update())
+}
+
+func TestScoreboardPaging(t *testing.T) {
+	t.Parallel()
+
+	source := input.NewFakeSource()
+	emitter := &recordingEmitter{}
+	scores := &fakeScores{count: 25}
+	scene := NewScoreboardScene(emitter, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), scores, &fakeSprints{}, &fakeScores{})
+
+	require.Len(t, scene.scores, 10)
+	assert.Equal(t, 1, scene.scores[0].Score)
+
+	press(t, scene, source, ebiten.KeyEnter) // Next Page
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, 2, scene.currentPage)
+	require.Len(t, scene.scores, 5)
+	assert.Equal(t, 21, scene.scores[0].Score)
+
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, 2, scene.currentPage, "no page after the last")
+	assert.Equal(t, []int{0, 1, 2}, scores.pages)
+
+	press(t, scene, source, ebiten.KeyDown) // Previous Page
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, 1, scene.currentPage)
+	assert.Equal(t, 11, scene.scores[0].Score)
+
+	press(t, scene, source, ebiten.KeyEnter)
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, 0, scene.currentPage, "no page before the first")
+	assert.Equal(t, 1, scene.scores[0].Score)
+	assert.Empty(t, emitter.events)
+
+	press(t, scene, source, ebiten.KeyDown) // Back
+	press(t, scene, source, ebiten.KeyEnter)
+	assert.Equal(t, []event.EventType{event.EventTypeMainMenu}, emitter.events)
+}
+
+func TestScoreboardSwitchesBoards(t *testing.T) {
+	t.Parallel()
+
+	source := input.NewFakeSource()
+	scores := &fakeScores{count: 25}
+	ultra := &fakeScores{count: 3}
+	scene := NewScoreboardScene(&recordingEmitter{}, input.NewInputManager(source, input.DefaultSettings(), input.DefaultBindings()), scores, &fakeSprints{count: 12}, ultra)
+
+	press(t, scene, source, ebiten.KeyEnter) // Next Page
+	assert.Equal(t, 1, scene.currentPage)
+
+	press(t, scene, source, ebiten.KeyRight)
+	assert.Equal(t, sim.ModeSprint, boards[scene.board])
+	assert.Equal(t, 0, scene.currentPage, "a new board starts on its first page")
+	require.Len(t, scene.sprints, 10)
+	assert.Equal(t, sim.TickRate, scene.sprints[0].Ticks)
+
+	press(t, scene, source, ebiten.KeyEnter)
+	require.Len(t, scene.sprints, 2)
+
+	press(t, scene, source, ebiten.KeyRight)
+	assert.Equal(t, sim.ModeUltra, boards[scene.board])
+	assert.Len(t, scene.scores, 3)
+	assert.Equal(t, []int{0}, ultra.pages)
+
+	press(t, scene, source, ebiten.KeyRight)
+	assert.Equal(t, sim.ModeMarathon, boards[scene.board], "wraps around")
+	assert.Len(t, scene.scores, 10)
+	press(t, scene, source, ebiten.KeyLeft)
+	assert.Equal(t, sim.ModeUltra, boards[scene.board])
+}
@@ -0,0 +1,30 @@
+package sim
+
+import "time"
+
+// maxCatchUpTicks caps how many ticks one Advance returns, so a driver that
+// stalls for a while does not try to replay the whole gap at once.
+const maxCatchUpTicks = 10
+
+// Clock turns wall time into ticks for drivers that are not called exactly
+// TickRate times a second, such as a server loop.
+type Clock struct {
+	pending time.Duration
+}
+
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// Advance adds the wall time that passed since the last call and returns how
+// many ticks to play. Time that does not fill a whole tick carries over.
+func (c *Clock) Advance(elapsed time.Duration) int {
+	c.pending += elapsed
+	ticks := int(c.pending * TickRate / time.Second) // Only whole ticks, unlike Ticks
+	c.pending -= Duration(ticks)
+	if ticks > maxCatchUpTicks {
+		ticks = maxCatchUpTicks
+		c.pending = 0
+	}
+	return ticks
+}
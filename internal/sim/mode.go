@@ -0,0 +1,42 @@
+package sim
+
+// Mode is the goal a game is played towards.
+type Mode int
+
+const (
+	// ModeMarathon is played until the stack tops out.
+	ModeMarathon Mode = iota
+	// ModeSprint is won by clearing SprintLines lines, as fast as possible.
+	ModeSprint
+	// ModeUltra is won by scoring as much as possible before UltraTicks run out.
+	ModeUltra
+)
+
+const (
+	SprintLines      = 40
+	SprintSplitLines = 10 // A split time is taken every this many lines
+
+	UltraTicks = 3 * 60 * TickRate // Three minutes
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeMarathon:
+		return "Marathon"
+	case ModeSprint:
+		return "Sprint"
+	case ModeUltra:
+		return "Ultra"
+	}
+	return ""
+}
+
+// Splits returns the tick each multiple of SprintSplitLines was reached at,
+// given the tick of every cleared line.
+func Splits(lineTicks []int) []int {
+	var splits []int
+	for lines := SprintSplitLines; lines <= len(lineTicks); lines += SprintSplitLines {
+		splits = append(splits, lineTicks[lines-1])
+	}
+	return splits
+}
@@ -0,0 +1,166 @@
+// Package sim runs games one tick at a time, independent of any frame loop.
+// A tick is the unit every duration in the tetris package is counted in, so
+// games advance the same way whether they are drawn at 60 FPS, replayed at
+// four times the speed or played by a bot as fast as the CPU allows.
+package sim
+
+import (
+	"time"
+
+	"github.com/piotrowski/ebitris/internal/tetris"
+)
+
+// TickRate is how many ticks make up one second of game time.
+const TickRate = 60
+
+// DangerRows is how close to the top the stack gets before the game is in danger.
+const DangerRows = 5
+
+// Ticks returns d in ticks, rounded to the nearest tick. A tick does not last
+// a whole number of nanoseconds, so rounding is what makes Ticks undo Duration.
+func Ticks(d time.Duration) int {
+	return int((d*TickRate + time.Second/2) / time.Second)
+}
+
+// Duration returns the game time taken by the given number of ticks.
+func Duration(ticks int) time.Duration {
+	return time.Duration(ticks) * time.Second / TickRate
+}
+
+// Result reports what happened during one tick.
+type Result struct {
+	Tick     int                 // Tick that was just played, starting at 1
+	Applied  []tetris.Action     // Actions that had an effect, in the order they were given
+	Locks    []tetris.LockResult // Pieces locked during the tick
+	Danger   bool                // Whether the stack is within DangerRows of the top
+	GameOver bool                // Whether the stack topped out
+	Complete bool                // Whether the goal of the mode has been reached
+
+	DangerChanged bool // Danger differs from the previous tick
+}
+
+// Simulation owns a game and moves it forward one tick per Step. Callers feed
+// it the actions for each tick and read the state back for drawing.
+type Simulation struct {
+	mode   Mode
+	state  *tetris.GameState
+	tick   int
+	danger bool
+
+	lineTicks []int // Tick each line was cleared at, in order
+	pieces    int   // Pieces locked so far
+	complete  bool
+}
+
+func New(mode Mode, opts tetris.Options) *Simulation {
+	return &Simulation{mode: mode, state: tetris.NewGameState(opts)}
+}
+
+func (s *Simulation) Mode() Mode {
+	return s.mode
+}
+
+// State returns the game for reading. Changes must go through Step.
+func (s *Simulation) State() *tetris.GameState {
+	return s.state
+}
+
+// Tick returns the number of ticks played so far.
+func (s *Simulation) Tick() int {
+	return s.tick
+}
+
+// Elapsed returns the game time played so far.
+func (s *Simulation) Elapsed() time.Duration {
+	return Duration(s.tick)
+}
+
+// LineTicks returns the tick each line was cleared at. In Sprint it stops at
+// SprintLines, even when the last clear went past it.
+func (s *Simulation) LineTicks() []int {
+	return s.lineTicks
+}
+
+// Pieces returns the number of pieces locked so far.
+func (s *Simulation) Pieces() int {
+	return s.pieces
+}
+
+// Complete reports whether the goal of the mode has been reached.
+func (s *Simulation) Complete() bool {
+	return s.complete
+}
+
+// Done reports whether the game has ended, either way.
+func (s *Simulation) Done() bool {
+	return s.complete || s.state.IsGameOver()
+}
+
+// Remaining returns the game time left in Ultra, and zero in the other modes.
+func (s *Simulation) Remaining() time.Duration {
+	if s.mode != ModeUltra {
+		return 0
+	}
+	return Duration(UltraTicks - s.tick)
+}
+
+func (s *Simulation) Pause() {
+	s.state.Pause()
+}
+
+func (s *Simulation) Resume() {
+	s.state.Resume()
+}
+
+// Step applies the actions in order and then advances the game by one tick.
+// Nothing happens once the game is done.
+func (s *Simulation) Step(actions []tetris.Action) Result {
+	if s.Done() {
+		return Result{Tick: s.tick, Danger: s.danger, GameOver: s.state.IsGameOver(), Complete: s.complete}
+	}
+
+	s.tick++
+	result := Result{
+		Tick:     s.tick,
+		Applied:  s.state.Step(actions),
+		Locks:    s.state.LockResults(),
+		GameOver: s.state.IsGameOver(),
+	}
+
+	board := s.state.GetBoard()
+	result.Danger = board.StackHeight() > board.Height-DangerRows
+	result.DangerChanged = result.Danger != s.danger
+	s.danger = result.Danger
+
+	s.pieces += len(result.Locks)
+	for _, lock := range result.Locks {
+		for range lock.Lines {
+			s.lineTicks = append(s.lineTicks, s.tick)
+		}
+	}
+	switch {
+	case s.mode == ModeSprint && len(s.lineTicks) >= SprintLines:
+		s.lineTicks = s.lineTicks[:SprintLines]
+		s.complete = true
+	case s.mode == ModeUltra && s.tick >= UltraTicks:
+		s.complete = true
+	}
+	result.Complete = s.complete
+
+	return result
+}
+
+// Controller decides the actions for each tick, for bots and scripted players.
+type Controller interface {
+	Actions(state *tetris.GameState) []tetris.Action
+}
+
+// Run plays ticks chosen by the controller until the game is done or
+// maxTicks have been played, and returns the number of ticks played.
+func (s *Simulation) Run(controller Controller, maxTicks int) int {
+	start := s.tick
+	for s.tick-start < maxTicks && !s.Done() {
+		s.Step(controller.Actions(s.state))
+	}
+	return s.tick - start
+}
@@ -0,0 +1,370 @@
+package sim
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/piotrowski/ebitris/internal/tetris"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// randomBot shuffles each piece sideways a little before hard dropping it.
+type randomBot struct {
+	rng *rand.Rand
+}
+
+func newRandomBot(seed uint64) *randomBot {
+	return &randomBot{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (b *randomBot) Actions(state *tetris.GameState) []tetris.Action {
+	switch b.rng.IntN(4) {
+	case 0:
+		return []tetris.Action{tetris.ActionMoveLeft}
+	case 1:
+		return []tetris.Action{tetris.ActionMoveRight}
+	case 2:
+		return []tetris.Action{tetris.ActionRotate}
+	}
+	return []tetris.Action{tetris.ActionHardDrop}
+}
+
+// greedyBot places each piece where it leaves the flattest stack with the
+// fewest holes, trying every rotation and column.
+type greedyBot struct{}
+
+func (greedyBot) Actions(state *tetris.GameState) []tetris.Action {
+	piece := state.GetCurrentPiece()
+	if piece == nil {
+		return nil
+	}
+	board := state.GetBoard()
+
+	var best []tetris.Action
+	bestScore := math.Inf(-1)
+	for rotations := range 4 {
+		candidate := piece.Clone()
+		for range rotations {
+			candidate.Rotate()
+		}
+		for dx := -board.Width; dx <= board.Width; dx++ {
+			if board.IsColliding(candidate, dx, 0) || !reachable(board, candidate, dx) {
+				continue
+			}
+			landed := candidate.Clone()
+			landed.X += dx
+			for !board.IsColliding(landed, 0, 1) {
+				landed.MoveDown()
+			}
+
+			if score := evaluate(board, landed); score > bestScore {
+				bestScore = score
+				best = placement(rotations, dx)
+			}
+		}
+	}
+	return best
+}
+
+// patientBot plays like greedyBot, but only places a piece every wait ticks,
+// so the level and with it gravity stay low for a long game.
+type patientBot struct {
+	wait, tick int
+}
+
+func (b *patientBot) Actions(state *tetris.GameState) []tetris.Action {
+	b.tick++
+	if b.tick%b.wait != 0 {
+		return nil
+	}
+	return greedyBot{}.Actions(state)
+}
+
+// reachable reports whether the piece can slide dx columns along its spawn rows.
+func reachable(board *tetris.Board, piece *tetris.Piece, dx int) bool {
+	step := 1
+	if dx < 0 {
+		step = -1
+	}
+	for x := step; x != dx+step && dx != 0; x += step {
+		if board.IsColliding(piece, x, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func placement(rotations, dx int) []tetris.Action {
+	var actions []tetris.Action
+	for range rotations {
+		actions = append(actions, tetris.ActionRotate)
+	}
+	move := tetris.ActionMoveRight
+	if dx < 0 {
+		move, dx = tetris.ActionMoveLeft, -dx
+	}
+	for range dx {
+		actions = append(actions, move)
+	}
+	return append(actions, tetris.ActionHardDrop)
+}
+
+// evaluate scores the visible field with the piece locked in, using the
+// usual weights for height, cleared lines, holes and bumpiness.
+func evaluate(board *tetris.Board, piece *tetris.Piece) float64 {
+	filled := make([][]bool, board.Height)
+	for y := range filled {
+		filled[y] = make([]bool, board.Width)
+		for x := range filled[y] {
+			filled[y][x] = board.Cell(x, y) != 0
+		}
+	}
+	for _, cell := range piece.GetCells() {
+		if y := piece.Y + cell.Y; y >= 0 {
+			filled[y][piece.X+cell.X] = true
+		}
+	}
+
+	var lines int
+	rows := filled[:0]
+	for _, row := range filled {
+		if slices.Contains(row, false) {
+			rows = append(rows, row)
+		} else {
+			lines++
+		}
+	}
+
+	var height, holes, bumpiness, previous int
+	for x := range board.Width {
+		column := 0
+		for y, row := range rows {
+			if row[x] {
+				if column == 0 {
+					column = len(rows) - y
+				}
+			} else if column > 0 {
+				holes++
+			}
+		}
+		height += column
+		if x > 0 {
+			bumpiness += max(column-previous, previous-column)
+		}
+		previous = column
+	}
+
+	return -0.51*float64(height) + 0.76*float64(lines) - 0.36*float64(holes) - 0.18*float64(bumpiness)
+}
+
+func TestTicks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		ticks    int
+	}{
+		{name: "zero", duration: 0, ticks: 0},
+		{name: "under half a tick", duration: 5 * time.Millisecond, ticks: 0},
+		{name: "over half a tick", duration: 10 * time.Millisecond, ticks: 1},
+		{name: "one tick", duration: Duration(1), ticks: 1},
+		{name: "one second", duration: time.Second, ticks: TickRate},
+		{name: "two minutes", duration: 2 * time.Minute, ticks: 120 * TickRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.ticks, Ticks(tt.duration))
+			assert.Equal(t, tt.ticks, Ticks(Duration(tt.ticks)))
+		})
+	}
+}
+
+func TestTicksRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for ticks := range 10 * TickRate {
+		assert.Equal(t, ticks, Ticks(Duration(ticks)), "%d ticks", ticks)
+	}
+	assert.Equal(t, UltraTicks, Ticks(Duration(UltraTicks)))
+}
+
+func TestStep(t *testing.T) {
+	t.Parallel()
+
+	s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: 1})
+
+	result := s.Step([]tetris.Action{tetris.ActionHardDrop})
+	assert.Equal(t, 1, result.Tick)
+	assert.Equal(t, []tetris.Action{tetris.ActionHardDrop}, result.Applied)
+	assert.Len(t, result.Locks, 1)
+	assert.False(t, result.Danger)
+	assert.False(t, result.GameOver)
+
+	result = s.Step(nil)
+	assert.Equal(t, 2, result.Tick)
+	assert.Empty(t, result.Locks)
+	assert.Equal(t, 2*time.Second/TickRate, s.Elapsed())
+}
+
+func TestStepDangerAndGameOver(t *testing.T) {
+	t.Parallel()
+
+	s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: 1})
+
+	var dangerChanges int
+	var result Result
+	for !result.GameOver {
+		result = s.Step([]tetris.Action{tetris.ActionHardDrop})
+		if result.DangerChanged {
+			dangerChanges++
+		}
+	}
+	assert.True(t, result.Danger)
+	assert.Equal(t, 1, dangerChanges)
+
+	tick := s.Tick()
+	result = s.Step([]tetris.Action{tetris.ActionHardDrop})
+	assert.True(t, result.GameOver)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, tick, s.Tick(), "a finished game does not advance")
+
+	// Pausing on the frame after the top out must not bring the game back
+	s.Pause()
+	s.Resume()
+	assert.True(t, s.Done())
+	assert.True(t, s.State().IsGameOver())
+	result = s.Step([]tetris.Action{tetris.ActionHardDrop})
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, tick, s.Tick())
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	play := func() (*Simulation, int) {
+		s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: 42})
+		return s, s.Run(newRandomBot(7), 100_000)
+	}
+
+	first, ticks := play()
+	second, _ := play()
+	require.True(t, first.State().IsGameOver())
+	assert.Equal(t, ticks, first.Tick())
+	assert.Equal(t, first.State().GetScore(), second.State().GetScore())
+	assert.Equal(t, first.Tick(), second.Tick())
+}
+
+func TestRunStopsAtMaxTicks(t *testing.T) {
+	t.Parallel()
+
+	s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: 1})
+	assert.Equal(t, 30, s.Run(newRandomBot(1), 30))
+	assert.Equal(t, 30, s.Tick())
+}
+
+func TestThousandsOfGames(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	for seed := range uint64(2000) {
+		s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: seed})
+		s.Run(newRandomBot(seed), 100_000)
+		require.True(t, s.State().IsGameOver())
+	}
+	t.Logf("2000 games in %v", time.Since(start))
+}
+
+func TestSprint(t *testing.T) {
+	t.Parallel()
+
+	s := New(ModeSprint, tetris.Options{Width: 10, Height: 20, Seed: 3})
+	s.Run(greedyBot{}, 100_000)
+
+	require.True(t, s.Complete())
+	assert.True(t, s.Done())
+	assert.False(t, s.State().IsGameOver())
+	require.Len(t, s.LineTicks(), SprintLines)
+	assert.Equal(t, s.Tick(), s.LineTicks()[SprintLines-1], "the clock stops on the last line")
+	assert.True(t, slices.IsSorted(s.LineTicks()))
+
+	splits := Splits(s.LineTicks())
+	require.Len(t, splits, SprintLines/SprintSplitLines)
+	assert.Equal(t, s.LineTicks()[9], splits[0])
+	assert.Equal(t, s.Tick(), splits[3])
+
+	result := s.Step([]tetris.Action{tetris.ActionHardDrop})
+	assert.True(t, result.Complete)
+	assert.Empty(t, result.Applied, "a finished sprint does not go on")
+}
+
+func TestMarathonKeepsGoingPastSprintLines(t *testing.T) {
+	t.Parallel()
+
+	s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: 3})
+	s.Run(greedyBot{}, 200)
+
+	assert.False(t, s.Done())
+	assert.Greater(t, len(s.LineTicks()), SprintLines)
+}
+
+func TestUltra(t *testing.T) {
+	t.Parallel()
+
+	s := New(ModeUltra, tetris.Options{Width: 10, Height: 20, Seed: 3})
+	bot := &patientBot{wait: 120}
+	assert.Equal(t, 3*time.Minute, s.Remaining())
+
+	assert.Equal(t, UltraTicks-1, s.Run(bot, UltraTicks-1))
+	assert.False(t, s.Done())
+	assert.Equal(t, time.Second/TickRate, s.Remaining())
+
+	result := s.Step(bot.Actions(s.State()))
+	require.True(t, result.Complete, "time is up")
+	assert.True(t, s.Done())
+	assert.False(t, s.State().IsGameOver())
+	assert.Zero(t, s.Remaining())
+	assert.Positive(t, s.State().GetScore())
+
+	score := s.State().GetScore()
+	result = s.Step([]tetris.Action{tetris.ActionHardDrop})
+	assert.Empty(t, result.Applied, "nothing is scored after time is up")
+	assert.Equal(t, score, s.State().GetScore())
+	assert.Equal(t, UltraTicks, s.Tick())
+}
+
+func TestSplits(t *testing.T) {
+	t.Parallel()
+
+	lineTicks := make([]int, 25)
+	for i := range lineTicks {
+		lineTicks[i] = (i + 1) * 10
+	}
+	assert.Equal(t, []int{100, 200}, Splits(lineTicks))
+	assert.Empty(t, Splits(lineTicks[:9]))
+}
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	c := NewClock()
+	assert.Equal(t, 0, c.Advance(10*time.Millisecond))
+	assert.Equal(t, 1, c.Advance(10*time.Millisecond), "leftover time carries over")
+	assert.Equal(t, 6, c.Advance(100*time.Millisecond))
+	assert.Equal(t, maxCatchUpTicks, c.Advance(time.Minute), "a long stall is not replayed")
+	assert.Equal(t, 0, c.Advance(0))
+}
+
+func BenchmarkGame(b *testing.B) {
+	for i := range b.N {
+		s := New(ModeMarathon, tetris.Options{Width: 10, Height: 20, Seed: uint64(i)})
+		s.Run(newRandomBot(uint64(i)), 100_000)
+	}
+}
@@ -0,0 +1,58 @@
+package tetris
+
+// Action is a single player command. Feeding the same actions on the same
+// frames to games created with the same Options reproduces the game exactly.
+type Action int
+
+const (
+	ActionMoveLeft Action = iota + 1
+	ActionMoveRight
+	ActionRotate
+	ActionRotateCCW
+	ActionMoveDown
+	ActionHardDrop
+	ActionHold
+)
+
+// Apply performs the action and reports whether it had any effect. Actions
+// are ignored while the game is not running and during the entry delay.
+func (gs *GameState) Apply(action Action) bool {
+	if gs.status != StatusPlaying || gs.currentPiece == nil {
+		return false
+	}
+
+	switch action {
+	case ActionMoveLeft:
+		return gs.MoveLeft()
+	case ActionMoveRight:
+		return gs.MoveRight()
+	case ActionRotate:
+		return gs.Rotate()
+	case ActionRotateCCW:
+		return gs.RotateCCW()
+	case ActionMoveDown:
+		return gs.MoveDown()
+	case ActionHardDrop:
+		gs.HardDrop()
+		return true
+	case ActionHold:
+		return gs.Hold()
+	}
+	return false
+}
+
+// Step advances the game by one frame: the actions are applied in order and
+// then gravity runs. It returns the actions that had an effect; the pieces
+// they locked are reported by LockResults.
+func (gs *GameState) Step(actions []Action) []Action {
+	gs.locks = nil
+
+	var applied []Action
+	for _, action := range actions {
+		if gs.Apply(action) {
+			applied = append(applied, action)
+		}
+	}
+	gs.Update()
+	return applied
+}
@@ -0,0 +1,82 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		action    Action
+		status    Status
+		expected  bool
+		expectedX int
+		expectedY int
+	}{
+		{name: "move left", action: ActionMoveLeft, status: StatusPlaying, expected: true, expectedX: 3, expectedY: 5},
+		{name: "move right", action: ActionMoveRight, status: StatusPlaying, expected: true, expectedX: 5, expectedY: 5},
+		{name: "move down", action: ActionMoveDown, status: StatusPlaying, expected: true, expectedX: 4, expectedY: 6},
+		{name: "rotate", action: ActionRotate, status: StatusPlaying, expected: true, expectedX: 4, expectedY: 5},
+		{name: "rotate counter-clockwise", action: ActionRotateCCW, status: StatusPlaying, expected: true, expectedX: 4, expectedY: 5},
+		{name: "ignored while paused", action: ActionMoveLeft, status: StatusPaused, expected: false, expectedX: 4, expectedY: 5},
+		{name: "unknown action", action: Action(0), status: StatusPlaying, expected: false, expectedX: 4, expectedY: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+			gs.status = tt.status
+			gs.currentPiece = NewPiece(ShapeT, 4, 5, 0)
+
+			assert.Equal(t, tt.expected, gs.Apply(tt.action))
+			assert.Equal(t, tt.expectedX, gs.currentPiece.X)
+			assert.Equal(t, tt.expectedY, gs.currentPiece.Y)
+		})
+	}
+}
+
+func TestStepReturnsAppliedActions(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	gs.currentPiece = NewPiece(ShapeO, 0, 5, 0)
+
+	applied := gs.Step([]Action{ActionMoveLeft, ActionMoveRight, ActionHardDrop})
+	assert.Equal(t, []Action{ActionMoveRight, ActionHardDrop}, applied)
+	assert.Equal(t, gs.gravity, gs.gravityProgress, "gravity ran once")
+}
+
+func TestStepReportsLocks(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	gs.linesCleared = 8
+	for _, y := range []int{17, 19} {
+		for x := 1; x < 10; x++ {
+			gs.board.row(y)[x] = 1
+		}
+	}
+	gs.board.row(18)[1] = 1
+	gs.currentPiece = NewPiece(ShapeI, -2, 5, 1)
+
+	gs.Step([]Action{ActionHardDrop})
+	locks := gs.LockResults()
+	if assert.Len(t, locks, 1) {
+		assert.Equal(t, []int{17, 19}, locks[0].Rows)
+		assert.Equal(t, 11, locks[0].HardDropRows)
+		assert.Equal(t, ShapeI, locks[0].Piece.Shape)
+		assert.Equal(t, ClearDouble, locks[0].ClearType())
+		assert.Equal(t, 300, locks[0].Points)
+		assert.Equal(t, 2, locks[0].Level)
+		assert.True(t, locks[0].LevelUp)
+	}
+
+	gs.Step(nil)
+	assert.Empty(t, gs.LockResults())
+}
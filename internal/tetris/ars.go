@@ -0,0 +1,125 @@
+package tetris
+
+// ARS is the Arika Rotation System from the TGM series. Pieces spawn flat
+// side down, rest on the bottom of their bounding box and only kick one
+// column right or left. The I piece never kicks.
+type ARS struct{}
+
+func NewARS() *ARS {
+	return &ARS{}
+}
+
+func (ARS) Name() string {
+	return "ARS"
+}
+
+func (ARS) Cells(shape ShapeType, rotation int) []Cell {
+	return arsShapes[shape][rotation]
+}
+
+func (ARS) States(shape ShapeType) int {
+	return len(arsShapes[shape])
+}
+
+func (ARS) SpawnRotation(ShapeType) int {
+	return 0
+}
+
+func (a ARS) Kicks(board *Board, piece *Piece, to int) []Cell {
+	switch piece.Shape {
+	case ShapeI, ShapeO:
+		return noKicks
+	case ShapeT, ShapeJ, ShapeL:
+		if a.blockedInCenterColumn(board, piece, to) {
+			return noKicks
+		}
+	case ShapeS, ShapeZ:
+	}
+	return arsKicks
+}
+
+// blockedInCenterColumn implements the ARS center column rule for T, J and L:
+// scanning the rotated piece in reading order, if the first blocked cell sits
+// in the middle column of the bounding box the piece is not allowed to kick.
+func (ARS) blockedInCenterColumn(board *Board, piece *Piece, to int) bool {
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			if !containsCell(arsShapes[piece.Shape][to], x, y) {
+				continue
+			}
+			if board.isOccupied(piece.X+x, piece.Y+y) {
+				return x == 1
+			}
+		}
+	}
+	return false
+}
+
+func containsCell(cells []Cell, x, y int) bool {
+	for _, cell := range cells {
+		if cell.X == x && cell.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// arsKicks tries the basic rotation, then one column right, then one column left.
+var arsKicks = []Cell{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: -1, Y: 0}}
+
+// arsShapes lists the rotation states in clockwise order starting from the
+// spawn orientation. I, S and Z only have two states.
+var arsShapes = map[ShapeType][][]Cell{
+	ShapeI: {
+		// Rotation 0: Horizontal
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}},
+		// Rotation 1: Vertical
+		{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}},
+	},
+	ShapeO: {
+		// Only 1 rotation (square)
+		{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+	},
+	ShapeT: {
+		// Rotation 0: T pointing down
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 1: T pointing left
+		{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 2: T pointing up
+		{{X: 1, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		// Rotation 3: T pointing right
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}},
+	},
+	ShapeS: {
+		// Rotation 0: S horizontal
+		{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+		// Rotation 1: S vertical
+		{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	},
+	ShapeZ: {
+		// Rotation 0: Z horizontal
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		// Rotation 1: Z vertical
+		{{X: 2, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}},
+	},
+	ShapeJ: {
+		// Rotation 0: J foot down-right
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}},
+		// Rotation 1: J foot down-left
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+		// Rotation 2: J foot up-left
+		{{X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		// Rotation 3: J foot up-right
+		{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	},
+	ShapeL: {
+		// Rotation 0: L foot down-left
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 0, Y: 2}},
+		// Rotation 1: L foot up-left
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 2: L foot up-right
+		{{X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		// Rotation 3: L foot down-right
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+	},
+}
@@ -1,25 +1,43 @@
 package tetris
 
+import "slices"
+
+// BufferRows is the number of hidden rows above the visible field. Pieces
+// spawn there and cells that lock above the skyline are kept there.
+const BufferRows = 20
+
+// Board holds the visible field in rows 0 to Height-1 and the hidden buffer
+// above it in rows -BufferRows to -1.
 type Board struct {
 	Width  int
 	Height int
-	grid   [][]int // 0 for empty, 1-7 for different colors
+	rows   [][]int // Buffer rows first, then the visible ones; 0 for empty, 1-7 for different colors
 }
 
 func NewBoard(width, height int) *Board {
-	grid := make([][]int, height)
-	for i := range grid {
-		grid[i] = make([]int, width)
+	rows := make([][]int, BufferRows+height)
+	for i := range rows {
+		rows[i] = make([]int, width)
 	}
 	return &Board{
 		Width:  width,
 		Height: height,
-		grid:   grid,
+		rows:   rows,
 	}
 }
 
+// Cell returns the cell at x, y, where negative rows lie in the hidden buffer.
 func (b *Board) Cell(x, y int) int {
-	return b.grid[y][x]
+	return b.row(y)[x]
+}
+
+func (b *Board) row(y int) []int {
+	return b.rows[BufferRows+y]
+}
+
+// inside reports whether the row lies on the board, counting the buffer.
+func (b *Board) inside(y int) bool {
+	return y >= -BufferRows && y < b.Height
 }
 
 func (b *Board) IsColliding(piece *Piece, offsetX, offsetY int) bool {
@@ -31,63 +49,115 @@ func (b *Board) IsColliding(piece *Piece, offsetX, offsetY int) bool {
 			return true
 		}
 
-		if y >= b.Height {
-			return true
-		}
-
-		if y >= 0 && b.grid[y][x] != 0 {
+		if !b.inside(y) || b.row(y)[x] != 0 {
 			return true
 		}
 	}
 	return false
 }
 
+// isOccupied reports whether the cell is filled or lies outside the walls, floor or buffer.
+func (b *Board) isOccupied(x, y int) bool {
+	if x < 0 || x >= b.Width || !b.inside(y) {
+		return true
+	}
+	return b.row(y)[x] != 0
+}
+
 func (b *Board) LockPiece(piece *Piece) {
 	for _, cell := range piece.GetCells() {
 		x := piece.X + cell.X
 		y := piece.Y + cell.Y
-		if x >= 0 && x < b.Width && y >= 0 && y < b.Height {
-			b.grid[y][x] = int(piece.Color)
+		if x >= 0 && x < b.Width && b.inside(y) {
+			b.row(y)[x] = int(piece.Color)
 		}
 	}
 }
 
 func (b *Board) ClearFullLines() int {
-	linesCleared := 0
+	return len(b.ClearFullRows())
+}
+
+// ClearFullRows removes every full row and returns their indices from top to
+// bottom, as they were numbered before the rows above collapsed.
+func (b *Board) ClearFullRows() []int {
+	rows := b.FullRows()
+	b.ClearRows(rows)
+	return rows
+}
 
-	for y := b.Height - 1; y >= 0; y-- {
+// FullRows returns the indices of the full rows from top to bottom without
+// removing them. Rows in the buffer count too.
+func (b *Board) FullRows() []int {
+	var rows []int
+	for y := -BufferRows; y < b.Height; y++ {
 		if b.isLineFull(y) {
-			b.removeLine(y)
-			linesCleared++
-			y++ // Check the same line again after shifting down
+			rows = append(rows, y)
 		}
 	}
+	return rows
+}
 
-	return linesCleared
+// ClearRows removes the given rows, listed from top to bottom, and collapses the stack above them.
+func (b *Board) ClearRows(rows []int) {
+	// Removing top to bottom only shifts rows that were already removed
+	for _, y := range rows {
+		b.removeLine(y)
+	}
 }
 
 func (b *Board) isLineFull(y int) bool {
-	for x := 0; x < b.Width; x++ {
-		if b.grid[y][x] == 0 {
+	return !slices.Contains(b.row(y), 0)
+}
+
+func (b *Board) removeLine(lineY int) {
+	for i := BufferRows + lineY; i > 0; i-- {
+		b.rows[i] = b.rows[i-1]
+	}
+	b.rows[0] = make([]int, b.Width)
+}
+
+// isEmptyAfterClearing reports whether nothing outside the given rows is occupied.
+func (b *Board) isEmptyAfterClearing(rows []int) bool {
+	for y := -BufferRows; y < b.Height; y++ {
+		if !slices.Contains(rows, y) && !b.isRowEmpty(y) {
 			return false
 		}
 	}
 	return true
 }
 
-func (b *Board) removeLine(lineY int) {
-	for y := lineY; y > 0; y-- {
-		b.grid[y] = b.grid[y-1]
+func (b *Board) isRowEmpty(y int) bool {
+	return !slices.ContainsFunc(b.row(y), func(cell int) bool { return cell != 0 })
+}
+
+// StackHeight returns how many rows from the floor up to the highest occupied
+// cell, 0 for an empty board. A stack reaching into the buffer is taller than Height.
+func (b *Board) StackHeight() int {
+	for y := -BufferRows; y < b.Height; y++ {
+		if !b.isRowEmpty(y) {
+			return b.Height - y
+		}
 	}
-	b.grid[0] = make([]int, b.Width)
+	return 0
 }
 
-// IsGameOver checks if any cells in the top row are occupied, which indicates game over.
-func (b *Board) IsGameOver() bool {
-	for x := 0; x < b.Width; x++ {
-		if b.grid[0][x] != 0 {
-			return true
+// AddGarbage pushes the stack up by one row per entry in holes and fills the
+// new rows at the bottom, leaving the given column of each one empty. It
+// reports whether any cell was pushed out past the top of the buffer.
+func (b *Board) AddGarbage(holes []int) bool {
+	var toppedOut bool
+	for _, hole := range holes {
+		toppedOut = toppedOut || !b.isRowEmpty(-BufferRows)
+		copy(b.rows, b.rows[1:])
+
+		garbage := make([]int, b.Width)
+		for x := range garbage {
+			if x != hole {
+				garbage[x] = int(PieceGarbage)
+			}
 		}
+		b.rows[len(b.rows)-1] = garbage
 	}
-	return false
+	return toppedOut
 }
@@ -41,7 +41,7 @@ func TestIsColliding(t *testing.T) {
 		},
 		{
 			name:     "collision with occupied cell",
-			board:    func() *Board { b := NewBoard(10, 20); b.grid[6][5] = 1; return b }(),
+			board:    func() *Board { b := NewBoard(10, 20); b.row(6)[5] = 1; return b }(),
 			piece:    &Piece{X: 5, Y: 5, Shape: ShapeI},
 			expected: true,
 		},
@@ -94,7 +94,7 @@ func TestLockPiece(t *testing.T) {
 			tt.board.LockPiece(tt.piece)
 			for y, row := range tt.expectedGrid {
 				for x, expectedColor := range row {
-					assert.Equal(t, expectedColor, tt.board.grid[y][x])
+					assert.Equal(t, expectedColor, tt.board.row(y)[x])
 				}
 			}
 		})
@@ -114,8 +114,8 @@ func TestClearFullLines(t *testing.T) {
 			name: "no full lines",
 			board: func() *Board {
 				b := NewBoard(5, 5)
-				b.grid[4][0] = 1
-				b.grid[4][1] = 1
+				b.row(4)[0] = 1
+				b.row(4)[1] = 1
 				return b
 			}(),
 			expectedCleared: 0,
@@ -131,11 +131,11 @@ func TestClearFullLines(t *testing.T) {
 			name: "single full line at bottom",
 			board: func() *Board {
 				b := NewBoard(5, 5)
-				b.grid[4][0] = 1
-				b.grid[4][1] = 1
-				b.grid[4][2] = 1
-				b.grid[4][3] = 1
-				b.grid[4][4] = 1
+				b.row(4)[0] = 1
+				b.row(4)[1] = 1
+				b.row(4)[2] = 1
+				b.row(4)[3] = 1
+				b.row(4)[4] = 1
 				return b
 			}(),
 			expectedCleared: 1,
@@ -151,13 +151,13 @@ func TestClearFullLines(t *testing.T) {
 			name: "full line with data above",
 			board: func() *Board {
 				b := NewBoard(3, 4)
-				b.grid[1][0] = 1
-				b.grid[2][0] = 1
-				b.grid[2][1] = 1
-				b.grid[2][2] = 1
-				b.grid[3][0] = 2
-				b.grid[3][1] = 2
-				b.grid[3][2] = 2
+				b.row(1)[0] = 1
+				b.row(2)[0] = 1
+				b.row(2)[1] = 1
+				b.row(2)[2] = 1
+				b.row(3)[0] = 2
+				b.row(3)[1] = 2
+				b.row(3)[2] = 2
 				return b
 			}(),
 			expectedCleared: 2,
@@ -178,9 +178,81 @@ func TestClearFullLines(t *testing.T) {
 			assert.Equal(t, tt.expectedCleared, cleared)
 			for y, row := range tt.expectedGrid {
 				for x, expectedValue := range row {
-					assert.Equal(t, expectedValue, tt.board.grid[y][x])
+					assert.Equal(t, expectedValue, tt.board.row(y)[x])
 				}
 			}
 		})
 	}
 }
+
+func TestClearFullRows(t *testing.T) {
+	t.Parallel()
+
+	b := boardFromRows(
+		"...",
+		"###",
+		"#..",
+		"###",
+		"##.",
+	)
+
+	assert.Equal(t, []int{1, 3}, b.ClearFullRows())
+	assert.Equal(t, []string{"...", "...", "...", "1..", "11."}, boardRows(b))
+	assert.Empty(t, b.ClearFullRows())
+}
+
+func TestStackHeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rows     []string
+		expected int
+	}{
+		{name: "empty", rows: []string{"...", "...", "..."}, expected: 0},
+		{name: "floor only", rows: []string{"...", "...", "#.#"}, expected: 1},
+		{name: "highest cell counts", rows: []string{"...", ".#.", "#.."}, expected: 2},
+		{name: "full to the top", rows: []string{"..#", "...", "..."}, expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, boardFromRows(tt.rows...).StackHeight())
+		})
+	}
+}
+
+func TestAddGarbage(t *testing.T) {
+	t.Parallel()
+
+	b := boardFromRows(
+		"...",
+		".#.",
+		"##.",
+	)
+
+	assert.False(t, b.AddGarbage([]int{1, 0}))
+	assert.Equal(t, []string{"11.", "9.9", ".99"}, boardRows(b))
+	assert.Equal(t, 1, b.Cell(1, -1), "the top of the stack is pushed into the buffer")
+	assert.Equal(t, 4, b.StackHeight())
+
+	b.row(-BufferRows)[2] = 1
+	assert.True(t, b.AddGarbage([]int{0}), "pushing a cell past the buffer tops out")
+}
+
+func TestBufferRows(t *testing.T) {
+	t.Parallel()
+
+	b := NewBoard(4, 4)
+	piece := &Piece{X: 0, Y: -3, Shape: ShapeI, Color: 1}
+	b.LockPiece(piece)
+	assert.Equal(t, 1, b.Cell(0, -2), "cells above the field are kept")
+	assert.Equal(t, []int{-2}, b.FullRows(), "full rows in the buffer count")
+	assert.False(t, b.IsColliding(&Piece{X: 0, Y: -BufferRows, Shape: ShapeO}, 0, 0))
+	assert.True(t, b.IsColliding(&Piece{X: 0, Y: -BufferRows - 2, Shape: ShapeO}, 0, 0), "nothing goes above the buffer")
+
+	b.ClearRows(b.FullRows())
+	assert.Equal(t, 0, b.StackHeight())
+}
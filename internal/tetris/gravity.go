@@ -0,0 +1,100 @@
+package tetris
+
+// Gravity is how far the falling piece drops each frame, counted in
+// 1/OneG rows so that fractions add up the same way on every machine.
+type Gravity int
+
+const (
+	OneG    Gravity = 1 << 16 // One row per frame
+	TwentyG Gravity = 20 * OneG
+
+	// InstantGravity is where pieces stop falling and appear on the stack as
+	// soon as they spawn or move, however tall the board is.
+	InstantGravity = TwentyG
+)
+
+// GravityFromFrames returns the gravity that drops one row every n frames.
+func GravityFromFrames(n int) Gravity {
+	n = max(n, 1)
+	return (OneG + Gravity(n) - 1) / Gravity(n)
+}
+
+// G returns the gravity in rows per frame.
+func (g Gravity) G() float64 {
+	return float64(g) / float64(OneG)
+}
+
+// GravityCurve decides how fast pieces fall at each level.
+type GravityCurve int
+
+const (
+	// GravityGuideline follows the Tetris Guideline formula, reaching 20G at level 19.
+	GravityGuideline GravityCurve = iota
+	// GravityNES uses the frames per row of the NES version, starting from its level 0.
+	GravityNES
+	// GravityTGM uses the internal gravity of Tetris The Grand Master, reaching 20G at level 11.
+	GravityTGM
+	// Gravity20G drops every piece instantly from the first level on.
+	Gravity20G
+)
+
+// guidelineGravity holds (0.8 - (level-1)*0.007)^(level-1) seconds per row
+// for levels 1 to 18, rounded up to the next Gravity step. Later levels are 20G.
+var guidelineGravity = []Gravity{
+	1093, 1378, 1769, 2311, 3076, 4169, 5759, 8107, 11635,
+	17027, 25416, 38709, 60169, 95484, 154743, 256187, 433425, 749597,
+}
+
+// nesFrames holds the frames per row of NES levels 0 to 28. Level 29 and
+// above drop a row every frame.
+var nesFrames = []int{
+	48, 43, 38, 33, 28, 23, 18, 13, 8, 6,
+	5, 5, 5, 4, 4, 4, 3, 3, 3, 2,
+	2, 2, 2, 2, 2, 2, 2, 2, 2,
+}
+
+// tgmGravity lists TGM's internal gravity, in 1/256 rows per frame, from the
+// internal level it takes effect at. The curve famously slows down twice.
+var tgmGravity = []struct {
+	level   int
+	gravity int
+}{
+	{0, 4}, {30, 6}, {35, 8}, {40, 10}, {50, 12}, {60, 16}, {70, 32}, {80, 48},
+	{90, 64}, {100, 80}, {120, 96}, {140, 112}, {160, 128}, {170, 144}, {200, 4},
+	{220, 32}, {230, 64}, {233, 96}, {236, 128}, {239, 160}, {243, 192}, {247, 224},
+	{251, 256}, {300, 512}, {330, 768}, {360, 1024}, {400, 1280}, {420, 1024},
+	{450, 768}, {500, 5120},
+}
+
+// tgmLevelsPerLevel maps a level of ten lines onto TGM's internal level, so
+// 20G arrives at level 11 as it does at internal level 500.
+const tgmLevelsPerLevel = 50
+
+// At returns the gravity at the given level, starting from 1.
+func (c GravityCurve) At(level int) Gravity {
+	level = max(level, 1)
+
+	switch c {
+	case GravityNES:
+		if level-1 < len(nesFrames) {
+			return GravityFromFrames(nesFrames[level-1])
+		}
+		return OneG
+	case GravityTGM:
+		internal := (level - 1) * tgmLevelsPerLevel
+		gravity := tgmGravity[0].gravity
+		for _, step := range tgmGravity {
+			if internal >= step.level {
+				gravity = step.gravity
+			}
+		}
+		return Gravity(gravity) * OneG / 256
+	case Gravity20G:
+		return TwentyG
+	}
+
+	if level-1 < len(guidelineGravity) {
+		return guidelineGravity[level-1]
+	}
+	return TwentyG
+}
@@ -0,0 +1,95 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGravityFromFrames(t *testing.T) {
+	t.Parallel()
+
+	for _, frames := range []int{1, 2, 3, 5, 48, 60} {
+		gravity := GravityFromFrames(frames)
+		assert.GreaterOrEqual(t, Gravity(frames)*gravity, OneG, "drops a row after %d frames", frames)
+		assert.Less(t, Gravity(frames-1)*gravity, OneG, "not before %d frames", frames)
+	}
+	assert.Equal(t, OneG, GravityFromFrames(0))
+}
+
+func TestGravityCurves(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		curve GravityCurve
+		level int
+		g     float64
+	}{
+		{name: "guideline level 1", curve: GravityGuideline, level: 1, g: 1.0 / 60},
+		{name: "guideline level 5", curve: GravityGuideline, level: 5, g: 0.04693},
+		{name: "guideline level 10", curve: GravityGuideline, level: 10, g: 0.2598},
+		{name: "guideline level 13", curve: GravityGuideline, level: 13, g: 0.9181},
+		{name: "guideline level 14 is above 1G", curve: GravityGuideline, level: 14, g: 1.4570},
+		{name: "guideline level 15", curve: GravityGuideline, level: 15, g: 2.3612},
+		{name: "guideline level 18", curve: GravityGuideline, level: 18, g: 11.4379},
+		{name: "guideline reaches 20G", curve: GravityGuideline, level: 19, g: 20},
+		{name: "guideline stays at 20G", curve: GravityGuideline, level: 40, g: 20},
+		{name: "nes level 0", curve: GravityNES, level: 1, g: 1.0 / 48},
+		{name: "nes level 8", curve: GravityNES, level: 9, g: 1.0 / 8},
+		{name: "nes level 9", curve: GravityNES, level: 10, g: 1.0 / 6},
+		{name: "nes level 19", curve: GravityNES, level: 20, g: 1.0 / 2},
+		{name: "nes kill screen", curve: GravityNES, level: 30, g: 1},
+		{name: "nes never passes 1G", curve: GravityNES, level: 99, g: 1},
+		{name: "tgm start", curve: GravityTGM, level: 1, g: 4.0 / 256},
+		{name: "tgm internal level 100", curve: GravityTGM, level: 3, g: 80.0 / 256},
+		{name: "tgm slows down at 200", curve: GravityTGM, level: 5, g: 4.0 / 256},
+		{name: "tgm internal level 300", curve: GravityTGM, level: 7, g: 2},
+		{name: "tgm internal level 400", curve: GravityTGM, level: 9, g: 5},
+		{name: "tgm slows down at 450", curve: GravityTGM, level: 10, g: 3},
+		{name: "tgm reaches 20G", curve: GravityTGM, level: 11, g: 20},
+		{name: "20G from the start", curve: Gravity20G, level: 1, g: 20},
+		{name: "levels below 1 count as 1", curve: GravityNES, level: 0, g: 1.0 / 48},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InEpsilon(t, tt.g, tt.curve.At(tt.level).G(), 0.001)
+		})
+	}
+}
+
+func TestGravityNeverSlowsDownOnGuideline(t *testing.T) {
+	t.Parallel()
+
+	for level := 2; level <= 30; level++ {
+		assert.GreaterOrEqual(t, GravityGuideline.At(level), GravityGuideline.At(level-1), "level %d", level)
+	}
+}
+
+func TestTwentyG(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, Gravity: Gravity20G})
+	piece := gs.GetCurrentPiece()
+	assert.True(t, gs.board.IsColliding(piece, 0, 1), "the first piece lands on spawn")
+
+	gs.HardDrop()
+	gs.Update()
+	assert.True(t, gs.board.IsColliding(gs.GetCurrentPiece(), 0, 1), "the next piece lands on spawn")
+
+	// A piece sliding off a ledge falls straight into the gap below
+	gs = NewGameState(Options{Width: 10, Height: 20, Seed: 1, Gravity: Gravity20G})
+	for x := range 4 {
+		gs.board.row(19)[x] = 1
+		gs.board.row(18)[x] = 1
+	}
+	gs.setCurrentPiece(NewPiece(ShapeO, 2, 0, 0))
+	assert.Equal(t, 16, gs.currentPiece.Y)
+	assert.True(t, gs.MoveRight())
+	assert.Equal(t, 16, gs.currentPiece.Y, "still resting on the ledge")
+	assert.True(t, gs.MoveRight())
+	assert.Equal(t, 18, gs.currentPiece.Y)
+}
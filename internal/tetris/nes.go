@@ -0,0 +1,47 @@
+package tetris
+
+// NES is the classic Nintendo rotation system. Pieces turn around a fixed
+// center, T, J and L spawn flat side up and a blocked rotation simply fails.
+type NES struct{}
+
+func NewNES() *NES {
+	return &NES{}
+}
+
+func (NES) Name() string {
+	return "NES"
+}
+
+func (NES) Cells(shape ShapeType, rotation int) []Cell {
+	return nesShapes[shape][rotation]
+}
+
+func (NES) States(shape ShapeType) int {
+	return len(nesShapes[shape])
+}
+
+func (NES) SpawnRotation(shape ShapeType) int {
+	switch shape {
+	case ShapeT, ShapeJ, ShapeL:
+		// Spawn pointing down, with the flat side up
+		return 2
+	case ShapeI, ShapeO, ShapeS, ShapeZ:
+	}
+	return 0
+}
+
+func (NES) Kicks(*Board, *Piece, int) []Cell {
+	return noKicks
+}
+
+// nesShapes reuses the SRS layouts: T, J and L turn around the same center,
+// while I, S and Z toggle between just two states and O never turns.
+var nesShapes = map[ShapeType][][]Cell{
+	ShapeI: {srsShapes[ShapeI][2], srsShapes[ShapeI][1]},
+	ShapeO: {srsShapes[ShapeO][0]},
+	ShapeT: srsShapes[ShapeT],
+	ShapeS: {srsShapes[ShapeS][2], srsShapes[ShapeS][1]},
+	ShapeZ: {srsShapes[ShapeZ][2], srsShapes[ShapeZ][1]},
+	ShapeJ: srsShapes[ShapeJ],
+	ShapeL: srsShapes[ShapeL],
+}
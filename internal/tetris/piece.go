@@ -13,6 +13,7 @@ const (
 	PieceMagenta
 	PieceOrange
 	PieceShadow
+	PieceGarbage
 )
 
 var shapeColors = map[ShapeType]PieceColor{
@@ -34,6 +35,7 @@ var PieceColors = map[PieceColor]color.Color{
 	PieceMagenta: color.RGBA{R: 255, G: 0, B: 255, A: 255},
 	PieceOrange:  color.RGBA{R: 255, G: 165, B: 0, A: 255},
 	PieceShadow:  color.RGBA{R: 40, G: 40, B: 50, A: 255},
+	PieceGarbage: color.RGBA{R: 128, G: 128, B: 128, A: 255},
 }
 
 func GetPieceColor[T ~int](c T) color.Color {
@@ -49,6 +51,8 @@ type Piece struct {
 	X        int
 	Y        int
 	Rotation int
+
+	rotationSystem RotationSystem // nil means SRS
 }
 
 type Cell struct {
@@ -65,6 +69,13 @@ func NewPiece(shape ShapeType, posX, posY, rotation int) *Piece {
 	}
 }
 
+// newSpawnedPiece creates a piece in the spawn orientation of the given rotation system.
+func newSpawnedPiece(rs RotationSystem, shape ShapeType, posX, posY int) *Piece {
+	piece := NewPiece(shape, posX, posY, rs.SpawnRotation(shape))
+	piece.rotationSystem = rs
+	return piece
+}
+
 func (p *Piece) Clone() *Piece {
 	return &Piece{
 		Shape:    p.Shape,
@@ -72,15 +83,45 @@ func (p *Piece) Clone() *Piece {
 		X:        p.X,
 		Y:        p.Y,
 		Rotation: p.Rotation,
+
+		rotationSystem: p.rotationSystem,
 	}
 }
 
 func (p *Piece) GetCells() []Cell {
-	return shapes[p.Shape][p.Rotation]
+	return p.getRotationSystem().Cells(p.Shape, p.Rotation)
 }
 
+// Rotate turns the piece clockwise.
 func (p *Piece) Rotate() {
-	p.Rotation = (p.Rotation + 1) % len(shapes[p.Shape])
+	p.Rotation = p.nextRotation(1)
+}
+
+// RotateCCW turns the piece counter-clockwise.
+func (p *Piece) RotateCCW() {
+	p.Rotation = p.nextRotation(-1)
+}
+
+func (p *Piece) nextRotation(direction int) int {
+	states := p.getRotationSystem().States(p.Shape)
+	return (p.Rotation + direction + states) % states
+}
+
+func (p *Piece) getRotationSystem() RotationSystem {
+	if p.rotationSystem == nil {
+		return SRS{}
+	}
+	return p.rotationSystem
+}
+
+// isAboveSkyline reports whether every cell of the piece is above the visible field.
+func (p *Piece) isAboveSkyline() bool {
+	for _, cell := range p.GetCells() {
+		if p.Y+cell.Y >= 0 {
+			return false
+		}
+	}
+	return true
 }
 
 func (p *Piece) MoveLeft() {
@@ -0,0 +1,144 @@
+package tetris
+
+import "math/rand/v2"
+
+// Randomizer decides the order in which pieces are dealt.
+type Randomizer interface {
+	Next() ShapeType
+}
+
+type RandomizerKind int
+
+const (
+	RandomizerSevenBag RandomizerKind = iota
+	RandomizerFourteenBag
+	RandomizerHistory
+	RandomizerPure
+)
+
+// NewRandomizer creates the randomizer of the given kind, seeded so that the
+// same seed always deals the same sequence.
+func NewRandomizer(kind RandomizerKind, seed uint64) Randomizer {
+	switch kind {
+	case RandomizerSevenBag:
+		return NewSevenBagRandomizer(seed)
+	case RandomizerFourteenBag:
+		return NewFourteenBagRandomizer(seed)
+	case RandomizerHistory:
+		return NewHistoryRandomizer(seed)
+	case RandomizerPure:
+		return NewPureRandomizer(seed)
+	}
+	return NewSevenBagRandomizer(seed)
+}
+
+func newRNG(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+// BagRandomizer shuffles a bag holding every shape a fixed number of times and
+// deals it out before refilling, so droughts are bounded by the bag size.
+type BagRandomizer struct {
+	rng    *rand.Rand
+	copies int
+	bag    []ShapeType
+}
+
+// NewSevenBagRandomizer deals every shape exactly once per seven pieces.
+func NewSevenBagRandomizer(seed uint64) *BagRandomizer {
+	return newBagRandomizer(seed, 1)
+}
+
+// NewFourteenBagRandomizer deals every shape exactly twice per fourteen pieces.
+func NewFourteenBagRandomizer(seed uint64) *BagRandomizer {
+	return newBagRandomizer(seed, 2)
+}
+
+func newBagRandomizer(seed uint64, copies int) *BagRandomizer {
+	return &BagRandomizer{
+		rng:    newRNG(seed),
+		copies: copies,
+		bag:    make([]ShapeType, 0, shapeCount*copies),
+	}
+}
+
+func (r *BagRandomizer) Next() ShapeType {
+	if len(r.bag) == 0 {
+		r.refill()
+	}
+	shape := r.bag[0]
+	r.bag = r.bag[1:]
+	return shape
+}
+
+func (r *BagRandomizer) refill() {
+	r.bag = r.bag[:0]
+	for range r.copies {
+		for id := range shapeCount {
+			r.bag = append(r.bag, ShapeType(id))
+		}
+	}
+	r.rng.Shuffle(len(r.bag), func(i, j int) {
+		r.bag[i], r.bag[j] = r.bag[j], r.bag[i]
+	})
+}
+
+// HistoryRandomizer is the TGM-style generator: it remembers the last four
+// pieces and rerolls up to four times when it draws one of them.
+type HistoryRandomizer struct {
+	rng     *rand.Rand
+	history [4]ShapeType
+	rolls   int
+	started bool
+}
+
+func NewHistoryRandomizer(seed uint64) *HistoryRandomizer {
+	return &HistoryRandomizer{
+		rng:     newRNG(seed),
+		history: [4]ShapeType{ShapeZ, ShapeZ, ShapeZ, ShapeZ},
+		rolls:   4,
+	}
+}
+
+func (r *HistoryRandomizer) Next() ShapeType {
+	var shape ShapeType
+	if !r.started {
+		// The first piece is never S, Z or O, which would force an overhang
+		r.started = true
+		firstPieces := []ShapeType{ShapeI, ShapeJ, ShapeL, ShapeT}
+		shape = firstPieces[r.rng.IntN(len(firstPieces))]
+	} else {
+		for range r.rolls {
+			shape = ShapeType(r.rng.IntN(shapeCount))
+			if !r.inHistory(shape) {
+				break
+			}
+		}
+	}
+
+	copy(r.history[1:], r.history[:len(r.history)-1])
+	r.history[0] = shape
+	return shape
+}
+
+func (r *HistoryRandomizer) inHistory(shape ShapeType) bool {
+	for _, s := range r.history {
+		if s == shape {
+			return true
+		}
+	}
+	return false
+}
+
+// PureRandomizer picks every piece independently with equal odds.
+type PureRandomizer struct {
+	rng *rand.Rand
+}
+
+func NewPureRandomizer(seed uint64) *PureRandomizer {
+	return &PureRandomizer{rng: newRNG(seed)}
+}
+
+func (r *PureRandomizer) Next() ShapeType {
+	return ShapeType(r.rng.IntN(shapeCount))
+}
@@ -0,0 +1,119 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func deal(r Randomizer, n int) []ShapeType {
+	shapes := make([]ShapeType, n)
+	for i := range shapes {
+		shapes[i] = r.Next()
+	}
+	return shapes
+}
+
+func countShapes(shapes []ShapeType) map[ShapeType]int {
+	counts := make(map[ShapeType]int)
+	for _, s := range shapes {
+		counts[s]++
+	}
+	return counts
+}
+
+func TestBagRandomizers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		randomizer Randomizer
+		bagSize    int
+		perBag     int
+	}{
+		{
+			name:       "7-bag deals every shape once per bag",
+			randomizer: NewSevenBagRandomizer(42),
+			bagSize:    7,
+			perBag:     1,
+		},
+		{
+			name:       "14-bag deals every shape twice per bag",
+			randomizer: NewFourteenBagRandomizer(42),
+			bagSize:    14,
+			perBag:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shapes := deal(tt.randomizer, tt.bagSize*100)
+			for start := 0; start < len(shapes); start += tt.bagSize {
+				counts := countShapes(shapes[start : start+tt.bagSize])
+				assert.Len(t, counts, shapeCount)
+				for shape, count := range counts {
+					assert.Equal(t, tt.perBag, count, "shape %d in bag starting at %d", shape, start)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryRandomizer(t *testing.T) {
+	t.Parallel()
+
+	for seed := range uint64(50) {
+		first := NewHistoryRandomizer(seed).Next()
+		assert.NotContains(t, []ShapeType{ShapeS, ShapeZ, ShapeO}, first)
+	}
+
+	shapes := deal(NewHistoryRandomizer(7), 7000)
+	for shape, count := range countShapes(shapes) {
+		assert.InDelta(t, 1000, count, 150, "shape %d", shape)
+	}
+
+	repeats := 0
+	for i := 1; i < len(shapes); i++ {
+		if shapes[i] == shapes[i-1] {
+			repeats++
+		}
+	}
+	// Pure random repeats one piece in seven; the history rerolls make it rare
+	assert.Less(t, float64(repeats)/float64(len(shapes)), 0.05)
+}
+
+func TestPureRandomizer(t *testing.T) {
+	t.Parallel()
+
+	shapes := deal(NewPureRandomizer(7), 7000)
+	counts := countShapes(shapes)
+	assert.Len(t, counts, shapeCount)
+	for shape, count := range counts {
+		assert.InDelta(t, 1000, count, 150, "shape %d", shape)
+	}
+}
+
+func TestRandomizersAreDeterministic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		newRandomizer func(seed uint64) Randomizer
+	}{
+		{name: "7-bag", newRandomizer: func(seed uint64) Randomizer { return NewSevenBagRandomizer(seed) }},
+		{name: "14-bag", newRandomizer: func(seed uint64) Randomizer { return NewFourteenBagRandomizer(seed) }},
+		{name: "history", newRandomizer: func(seed uint64) Randomizer { return NewHistoryRandomizer(seed) }},
+		{name: "pure", newRandomizer: func(seed uint64) Randomizer { return NewPureRandomizer(seed) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, deal(tt.newRandomizer(123), 100), deal(tt.newRandomizer(123), 100))
+			assert.NotEqual(t, deal(tt.newRandomizer(123), 100), deal(tt.newRandomizer(124), 100))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package tetris
+
+// RotationSystem decides what each piece looks like in every orientation,
+// which orientation it spawns in and how it is nudged when a rotation is blocked.
+type RotationSystem interface {
+	// Name returns a short identifier such as "SRS".
+	Name() string
+	// Cells returns the cells of shape in the given rotation state.
+	Cells(shape ShapeType, rotation int) []Cell
+	// States returns the number of rotation states of shape.
+	States(shape ShapeType) int
+	// SpawnRotation returns the rotation state new pieces of shape start in.
+	SpawnRotation(shape ShapeType) int
+	// Kicks returns the offsets to try, in order, when rotating piece into the
+	// state to. The piece is still in its original state when this is called.
+	Kicks(board *Board, piece *Piece, to int) []Cell
+}
+
+// RotationSystemByName returns the built-in rotation system with the given name.
+func RotationSystemByName(name string) (RotationSystem, bool) {
+	for _, rs := range []RotationSystem{NewSRS(), NewARS(), NewNES()} {
+		if rs.Name() == name {
+			return rs, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,163 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotationSystemShapes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		system         RotationSystem
+		expectedStates map[ShapeType]int
+	}{
+		{
+			name:           "SRS",
+			system:         NewSRS(),
+			expectedStates: map[ShapeType]int{ShapeI: 4, ShapeO: 4, ShapeT: 4, ShapeS: 4, ShapeZ: 4, ShapeJ: 4, ShapeL: 4},
+		},
+		{
+			name:           "ARS",
+			system:         NewARS(),
+			expectedStates: map[ShapeType]int{ShapeI: 2, ShapeO: 1, ShapeT: 4, ShapeS: 2, ShapeZ: 2, ShapeJ: 4, ShapeL: 4},
+		},
+		{
+			name:           "NES",
+			system:         NewNES(),
+			expectedStates: map[ShapeType]int{ShapeI: 2, ShapeO: 1, ShapeT: 4, ShapeS: 2, ShapeZ: 2, ShapeJ: 4, ShapeL: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.name, tt.system.Name())
+			for shape, states := range tt.expectedStates {
+				assert.Equal(t, states, tt.system.States(shape))
+				for rotation := 0; rotation < states; rotation++ {
+					assert.Len(t, tt.system.Cells(shape, rotation), 4)
+				}
+
+				piece := newSpawnedPiece(tt.system, shape, 0, 0)
+				for i := 0; i < states; i++ {
+					piece.Rotate()
+				}
+				assert.Equal(t, tt.system.SpawnRotation(shape), piece.Rotation)
+			}
+		})
+	}
+}
+
+func TestRotationSystemKicks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		system          RotationSystem
+		board           *Board
+		shape           ShapeType
+		startX          int
+		startY          int
+		startRot        int
+		expectedSuccess bool
+		expectedX       int
+		expectedRot     int
+	}{
+		{
+			name:            "ARS kicks T right off the left wall",
+			system:          NewARS(),
+			board:           NewBoard(10, 20),
+			shape:           ShapeT,
+			startX:          -1,
+			startY:          5,
+			startRot:        3,
+			expectedSuccess: true,
+			expectedX:       0,
+			expectedRot:     0,
+		},
+		{
+			name:   "ARS center column rule prevents the kick",
+			system: NewARS(),
+			board: func() *Board {
+				b := NewBoard(10, 20)
+				b.row(5)[4] = 1
+				return b
+			}(),
+			shape:           ShapeT,
+			startX:          3,
+			startY:          5,
+			startRot:        0,
+			expectedSuccess: false,
+			expectedX:       3,
+			expectedRot:     0,
+		},
+		{
+			name:            "ARS never kicks the I piece",
+			system:          NewARS(),
+			board:           NewBoard(10, 20),
+			shape:           ShapeI,
+			startX:          -2,
+			startY:          5,
+			startRot:        1,
+			expectedSuccess: false,
+			expectedX:       -2,
+			expectedRot:     1,
+		},
+		{
+			name:            "NES does not kick off the wall",
+			system:          NewNES(),
+			board:           NewBoard(10, 20),
+			shape:           ShapeT,
+			startX:          -1,
+			startY:          5,
+			startRot:        1,
+			expectedSuccess: false,
+			expectedX:       -1,
+			expectedRot:     1,
+		},
+		{
+			name:            "SRS kicks where NES does not",
+			system:          NewSRS(),
+			board:           NewBoard(10, 20),
+			shape:           ShapeT,
+			startX:          -1,
+			startY:          5,
+			startRot:        1,
+			expectedSuccess: true,
+			expectedX:       0,
+			expectedRot:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, RotationSystem: tt.system})
+			gs.board = tt.board
+			gs.currentPiece = newSpawnedPiece(tt.system, tt.shape, tt.startX, tt.startY)
+			gs.currentPiece.Rotation = tt.startRot
+
+			result := gs.Rotate()
+			assert.Equal(t, tt.expectedSuccess, result)
+			assert.Equal(t, tt.expectedX, gs.currentPiece.X)
+			assert.Equal(t, tt.expectedRot, gs.currentPiece.Rotation)
+		})
+	}
+}
+
+func TestNewGameStateUsesRotationSystem(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, RotationSystem: NewNES()})
+	piece := gs.GetCurrentPiece()
+	assert.Equal(t, NewNES().SpawnRotation(piece.Shape), piece.Rotation)
+	assert.Equal(t, nesShapes[piece.Shape][piece.Rotation], piece.GetCells())
+
+	gs = NewGameState(Options{})
+	assert.Equal(t, "SRS", gs.rotationSystem.Name())
+}
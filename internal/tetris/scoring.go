@@ -0,0 +1,225 @@
+package tetris
+
+// TSpin classifies how a T piece was spun into place before locking.
+type TSpin int
+
+const (
+	TSpinNone TSpin = iota
+	TSpinMini
+	TSpinFull
+)
+
+// tstKickIndex is the position of the SRS kick used by T-spin triples. A
+// spin that needs it always counts as a full T-spin, even if it looks like a mini.
+const tstKickIndex = 4
+
+// DetectTSpin applies the 3-corner rule: a T piece whose last successful
+// action was a rotation is spun in when at least three of the four cells
+// diagonal to its center are blocked. It is a full T-spin when both corners
+// on the pointing side are blocked, otherwise a mini.
+func DetectTSpin(board *Board, piece *Piece, rotatedLast bool, kickIndex int) TSpin {
+	if piece.Shape != ShapeT || !rotatedLast {
+		return TSpinNone
+	}
+
+	center, stem, ok := tCenterAndStem(piece.GetCells())
+	if !ok {
+		return TSpinNone
+	}
+
+	cx, cy := piece.X+center.X, piece.Y+center.Y
+	corners, frontCorners := 0, 0
+	for _, d := range []Cell{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: 1, Y: 1}} {
+		if !board.isOccupied(cx+d.X, cy+d.Y) {
+			continue
+		}
+		corners++
+		// Front corners lie on the side the T points to
+		if d.X == stem.X || d.Y == stem.Y {
+			frontCorners++
+		}
+	}
+
+	switch {
+	case corners < 3:
+		return TSpinNone
+	case frontCorners == 2 || kickIndex == tstKickIndex:
+		return TSpinFull
+	default:
+		return TSpinMini
+	}
+}
+
+// tCenterAndStem finds the center of a T piece, the cell touching the other
+// three, and the direction its stem points in. Working from the cells keeps
+// the rule independent of how a rotation system lays out its states.
+func tCenterAndStem(cells []Cell) (Cell, Cell, bool) {
+	for _, center := range cells {
+		var neighbors []Cell
+		for _, c := range cells {
+			d := Cell{X: c.X - center.X, Y: c.Y - center.Y}
+			if abs(d.X)+abs(d.Y) == 1 {
+				neighbors = append(neighbors, d)
+			}
+		}
+		if len(neighbors) != 3 {
+			continue
+		}
+		// The stem is the neighbor without a neighbor opposite to it
+		for _, n := range neighbors {
+			if !containsCell(neighbors, -n.X, -n.Y) {
+				return center, n, true
+			}
+		}
+	}
+	return Cell{}, Cell{}, false
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// LockScore describes how a piece lock was scored.
+type LockScore struct {
+	Lines        int
+	TSpin        TSpin
+	PerfectClear bool
+	Combo        int  // Consecutive line-clearing locks before this one, -1 when no lines were cleared
+	BackToBack   bool // Whether the back-to-back bonus was applied
+	Points       int
+}
+
+// ClearType names what a lock achieved, as shown to the player.
+type ClearType int
+
+const (
+	ClearNone ClearType = iota
+	ClearSingle
+	ClearDouble
+	ClearTriple
+	ClearTetris
+	ClearTSpinMini
+	ClearTSpin
+)
+
+func (c ClearType) String() string {
+	switch c {
+	case ClearNone:
+		return "None"
+	case ClearSingle:
+		return "Single"
+	case ClearDouble:
+		return "Double"
+	case ClearTriple:
+		return "Triple"
+	case ClearTetris:
+		return "Tetris"
+	case ClearTSpinMini:
+		return "T-Spin Mini"
+	case ClearTSpin:
+		return "T-Spin"
+	}
+	return ""
+}
+
+// ClearType classifies the lock. T-spins take precedence over the line count,
+// so a T-spin double is ClearTSpin with Lines set to 2.
+func (s LockScore) ClearType() ClearType {
+	switch s.TSpin {
+	case TSpinMini:
+		return ClearTSpinMini
+	case TSpinFull:
+		return ClearTSpin
+	case TSpinNone:
+	}
+	return [...]ClearType{ClearNone, ClearSingle, ClearDouble, ClearTriple, ClearTetris}[min(s.Lines, 4)]
+}
+
+var (
+	linePoints      = [5]int{0, 100, 300, 500, 800}
+	tSpinMiniPoints = [3]int{100, 200, 400}
+	tSpinPoints     = [4]int{400, 800, 1200, 1600}
+	perfectPoints   = [5]int{0, 800, 1200, 1800, 2000}
+)
+
+const (
+	comboPoints             = 50
+	backToBackPerfectTetris = 3200
+	softDropPointsPerCell   = 1
+	hardDropPointsPerCell   = 2
+)
+
+// Scorer implements guideline scoring. It keeps the combo and back-to-back
+// chains between locks, so one Scorer belongs to one game.
+type Scorer struct {
+	combo      int
+	backToBack bool
+}
+
+func NewScorer() *Scorer {
+	return &Scorer{combo: -1}
+}
+
+// Lock scores a piece that locked and cleared lines at the given level.
+func (s *Scorer) Lock(lines int, tspin TSpin, perfectClear bool, level int) LockScore {
+	result := LockScore{
+		Lines:        lines,
+		TSpin:        tspin,
+		PerfectClear: perfectClear,
+		Combo:        -1,
+	}
+
+	base := actionPoints(lines, tspin)
+	if lines == 0 {
+		// Locking without clearing breaks the combo but keeps back-to-back alive
+		s.combo = -1
+		result.Points = base * level
+		return result
+	}
+
+	difficult := lines == 4 || tspin != TSpinNone
+	if difficult && s.backToBack {
+		base = base * 3 / 2
+		result.BackToBack = true
+	}
+
+	s.combo++
+	result.Combo = s.combo
+	points := base + comboPoints*s.combo
+
+	if perfectClear {
+		if lines == 4 && result.BackToBack {
+			points += backToBackPerfectTetris
+		} else {
+			points += perfectPoints[min(lines, 4)]
+		}
+	}
+
+	s.backToBack = difficult
+	result.Points = points * level
+	return result
+}
+
+func actionPoints(lines int, tspin TSpin) int {
+	switch tspin {
+	case TSpinMini:
+		return tSpinMiniPoints[min(lines, len(tSpinMiniPoints)-1)]
+	case TSpinFull:
+		return tSpinPoints[min(lines, len(tSpinPoints)-1)]
+	case TSpinNone:
+	}
+	return linePoints[min(lines, len(linePoints)-1)]
+}
+
+// SoftDrop returns the points for soft dropping the given number of cells.
+func (s *Scorer) SoftDrop(cells int) int {
+	return cells * softDropPointsPerCell
+}
+
+// HardDrop returns the points for hard dropping the given number of cells.
+func (s *Scorer) HardDrop(cells int) int {
+	return cells * hardDropPointsPerCell
+}
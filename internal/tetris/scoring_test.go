@@ -0,0 +1,290 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectTSpin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		board       *Board
+		piece       *Piece
+		rotatedLast bool
+		kickIndex   int
+		expected    TSpin
+	}{
+		{
+			name: "T-spin double in a T slot",
+			board: boardFromRows(
+				"......",
+				"##....",
+				"#...##",
+				"##.###",
+			),
+			piece:       NewPiece(ShapeT, 1, 1, 2),
+			rotatedLast: true,
+			expected:    TSpinFull,
+		},
+		{
+			name: "same slot without a rotation is not a spin",
+			board: boardFromRows(
+				"......",
+				"##....",
+				"#...##",
+				"##.###",
+			),
+			piece:       NewPiece(ShapeT, 1, 1, 2),
+			rotatedLast: false,
+			expected:    TSpinNone,
+		},
+		{
+			name: "only two corners blocked",
+			board: boardFromRows(
+				"......",
+				"......",
+				"#...##",
+				"##.###",
+			),
+			piece:       NewPiece(ShapeT, 1, 1, 2),
+			rotatedLast: true,
+			expected:    TSpinNone,
+		},
+		{
+			name: "back corners and one front corner make a mini",
+			board: boardFromRows(
+				"......",
+				"#.....",
+				"......",
+				"######",
+			),
+			piece:       NewPiece(ShapeT, 0, 1, 0),
+			rotatedLast: true,
+			expected:    TSpinMini,
+		},
+		{
+			name: "walls count as blocked corners",
+			board: boardFromRows(
+				"......",
+				"......",
+				"......",
+				".#####",
+			),
+			piece:       NewPiece(ShapeT, -1, 1, 1),
+			rotatedLast: true,
+			expected:    TSpinMini,
+		},
+		{
+			name: "mini upgraded by the T-spin triple kick",
+			board: boardFromRows(
+				"......",
+				"#.....",
+				"......",
+				"######",
+			),
+			piece:       NewPiece(ShapeT, 0, 1, 0),
+			rotatedLast: true,
+			kickIndex:   tstKickIndex,
+			expected:    TSpinFull,
+		},
+		{
+			name: "other pieces never spin",
+			board: boardFromRows(
+				"......",
+				"##....",
+				"#...##",
+				"##.###",
+			),
+			piece:       NewPiece(ShapeS, 1, 1, 0),
+			rotatedLast: true,
+			expected:    TSpinNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.False(t, tt.board.IsColliding(tt.piece, 0, 0))
+			assert.Equal(t, tt.expected, DetectTSpin(tt.board, tt.piece, tt.rotatedLast, tt.kickIndex))
+		})
+	}
+}
+
+func TestDetectTSpinWithARS(t *testing.T) {
+	t.Parallel()
+
+	// ARS lays the T out differently, so the rule has to find the center and stem itself
+	board := boardFromRows(
+		"......",
+		"##....",
+		"#...##",
+		"##.###",
+	)
+	piece := newSpawnedPiece(NewARS(), ShapeT, 1, 1)
+	assert.Equal(t, TSpinFull, DetectTSpin(board, piece, true, 0))
+}
+
+func TestScorerLock(t *testing.T) {
+	t.Parallel()
+
+	type lock struct {
+		lines        int
+		tspin        TSpin
+		perfectClear bool
+	}
+
+	tests := []struct {
+		name           string
+		level          int
+		locks          []lock
+		expectedPoints []int
+	}{
+		{
+			name:           "line clears",
+			level:          1,
+			locks:          []lock{{lines: 1}, {}, {lines: 2}, {}, {lines: 3}, {}, {lines: 4}},
+			expectedPoints: []int{100, 0, 300, 0, 500, 0, 800},
+		},
+		{
+			name:           "points scale with level",
+			level:          3,
+			locks:          []lock{{lines: 1}, {}, {lines: 4}},
+			expectedPoints: []int{300, 0, 2400},
+		},
+		{
+			name:  "T-spins",
+			level: 1,
+			locks: []lock{
+				{tspin: TSpinMini}, {}, {lines: 1, tspin: TSpinMini}, {}, {lines: 2, tspin: TSpinMini},
+				{}, {tspin: TSpinFull}, {}, {lines: 1, tspin: TSpinFull},
+			},
+			expectedPoints: []int{100, 0, 200, 0, 600, 0, 400, 0, 1200},
+		},
+		{
+			name:           "T-spin double and triple without back-to-back",
+			level:          1,
+			locks:          []lock{{lines: 2, tspin: TSpinFull}, {}, {lines: 1}, {}, {lines: 3, tspin: TSpinFull}},
+			expectedPoints: []int{1200, 0, 100, 0, 1600},
+		},
+		{
+			name:           "combo adds 50 per consecutive clear",
+			level:          2,
+			locks:          []lock{{lines: 1}, {lines: 1}, {lines: 1}, {}, {lines: 1}},
+			expectedPoints: []int{200, 300, 400, 0, 200},
+		},
+		{
+			name:           "back-to-back tetris survives locks without clears",
+			level:          1,
+			locks:          []lock{{lines: 4}, {}, {lines: 4}},
+			expectedPoints: []int{800, 0, 1200},
+		},
+		{
+			name:           "back-to-back between T-spins and tetrises",
+			level:          1,
+			locks:          []lock{{lines: 2, tspin: TSpinFull}, {}, {lines: 4}, {}, {lines: 1, tspin: TSpinMini}},
+			expectedPoints: []int{1200, 0, 1200, 0, 300},
+		},
+		{
+			name:           "an easy clear breaks back-to-back",
+			level:          1,
+			locks:          []lock{{lines: 4}, {}, {lines: 1}, {}, {lines: 4}},
+			expectedPoints: []int{800, 0, 100, 0, 800},
+		},
+		{
+			name:           "T-spin without lines neither starts nor breaks back-to-back",
+			level:          1,
+			locks:          []lock{{tspin: TSpinFull}, {lines: 4}, {tspin: TSpinFull}, {lines: 4}},
+			expectedPoints: []int{400, 800, 400, 1200},
+		},
+		{
+			name:           "perfect clears",
+			level:          1,
+			locks:          []lock{{lines: 1, perfectClear: true}, {}, {lines: 4, perfectClear: true}},
+			expectedPoints: []int{900, 0, 2800},
+		},
+		{
+			name:           "back-to-back tetris perfect clear",
+			level:          1,
+			locks:          []lock{{lines: 4}, {}, {lines: 4, perfectClear: true}},
+			expectedPoints: []int{800, 0, 4400},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scorer := NewScorer()
+			points := make([]int, len(tt.locks))
+			for i, l := range tt.locks {
+				points[i] = scorer.Lock(l.lines, l.tspin, l.perfectClear, tt.level).Points
+			}
+			assert.Equal(t, tt.expectedPoints, points)
+		})
+	}
+}
+
+func TestScorerReportsChains(t *testing.T) {
+	t.Parallel()
+
+	scorer := NewScorer()
+
+	first := scorer.Lock(4, TSpinNone, false, 1)
+	assert.Equal(t, 0, first.Combo)
+	assert.False(t, first.BackToBack)
+
+	second := scorer.Lock(2, TSpinFull, false, 1)
+	assert.Equal(t, 1, second.Combo)
+	assert.True(t, second.BackToBack)
+	assert.Equal(t, 1800+50, second.Points)
+
+	miss := scorer.Lock(0, TSpinNone, false, 1)
+	assert.Equal(t, -1, miss.Combo)
+}
+
+func TestDropPoints(t *testing.T) {
+	t.Parallel()
+
+	scorer := NewScorer()
+	assert.Equal(t, 3, scorer.SoftDrop(3))
+	assert.Equal(t, 20, scorer.HardDrop(10))
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	gs.currentPiece = NewPiece(ShapeO, 4, 0, 0)
+	gs.MoveDown()
+	gs.MoveDown()
+	assert.Equal(t, 2, gs.GetScore())
+
+	gs.HardDrop() // Drops from row 2 to row 18
+	assert.Equal(t, 2+32, gs.GetScore())
+}
+
+func TestClearType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		score    LockScore
+		expected ClearType
+	}{
+		{score: LockScore{}, expected: ClearNone},
+		{score: LockScore{Lines: 1}, expected: ClearSingle},
+		{score: LockScore{Lines: 2}, expected: ClearDouble},
+		{score: LockScore{Lines: 3}, expected: ClearTriple},
+		{score: LockScore{Lines: 4}, expected: ClearTetris},
+		{score: LockScore{TSpin: TSpinMini}, expected: ClearTSpinMini},
+		{score: LockScore{Lines: 1, TSpin: TSpinMini}, expected: ClearTSpinMini},
+		{score: LockScore{Lines: 2, TSpin: TSpinFull}, expected: ClearTSpin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected.String(), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.score.ClearType())
+		})
+	}
+}
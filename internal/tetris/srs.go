@@ -0,0 +1,144 @@
+package tetris
+
+// SRS is the Super Rotation System used by modern guideline games. Pieces
+// spawn pointing up and try five kick offsets before a rotation fails.
+type SRS struct{}
+
+func NewSRS() *SRS {
+	return &SRS{}
+}
+
+func (SRS) Name() string {
+	return "SRS"
+}
+
+func (SRS) Cells(shape ShapeType, rotation int) []Cell {
+	return srsShapes[shape][rotation]
+}
+
+func (SRS) States(shape ShapeType) int {
+	return len(srsShapes[shape])
+}
+
+func (SRS) SpawnRotation(ShapeType) int {
+	return 0
+}
+
+func (SRS) Kicks(_ *Board, piece *Piece, to int) []Cell {
+	switch piece.Shape {
+	case ShapeI:
+		return iKicks[rotationTransition{from: piece.Rotation, to: to}]
+	case ShapeO:
+		return noKicks
+	case ShapeT, ShapeS, ShapeZ, ShapeJ, ShapeL:
+		return jlstzKicks[rotationTransition{from: piece.Rotation, to: to}]
+	}
+	return noKicks
+}
+
+// srsShapes gives every shape four rotation states (0, R, 2, L) laid out in
+// its bounding box with Y growing downwards.
+var srsShapes = map[ShapeType][][]Cell{
+	ShapeI: {
+		// Rotation 0: Horizontal
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}},
+		// Rotation R: Vertical, right column
+		{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}},
+		// Rotation 2: Horizontal, lower row
+		{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}},
+		// Rotation L: Vertical, left column
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}},
+	},
+	ShapeO: {
+		// The square looks the same in every rotation state
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+	},
+	ShapeT: {
+		// Rotation 0: T pointing up
+		{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+		// Rotation 1: T pointing right
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 2: T pointing down
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 3: T pointing left
+		{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	},
+	ShapeS: {
+		// Rotation 0: S horizontal
+		{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+		// Rotation 1: S vertical, right side
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}},
+		// Rotation 2: S horizontal, lower rows
+		{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+		// Rotation 3: S vertical, left side
+		{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	},
+	ShapeZ: {
+		// Rotation 0: Z horizontal
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+		// Rotation 1: Z vertical, right side
+		{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 2: Z horizontal, lower rows
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		// Rotation 3: Z vertical, left side
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 2}},
+	},
+	ShapeJ: {
+		// Rotation 0: J pointing up
+		{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+		// Rotation 1: J pointing right
+		{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+		// Rotation 2: J pointing down
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}},
+		// Rotation 3: J pointing left
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+	},
+	ShapeL: {
+		// Rotation 0: L pointing up
+		{{X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+		// Rotation 1: L pointing right
+		{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		// Rotation 2: L pointing down
+		{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 0, Y: 2}},
+		// Rotation 3: L pointing left
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	},
+}
+
+// rotationTransition identifies a rotation from one state to another.
+type rotationTransition struct {
+	from, to int
+}
+
+// Wall kick offsets from the Super Rotation System guideline, tried in order
+// until one fits. The guideline tables use Y growing upwards; these are
+// already flipped to match the board, where Y grows downwards.
+var (
+	jlstzKicks = map[rotationTransition][]Cell{
+		{from: 0, to: 1}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: 2}, {X: -1, Y: 2}},
+		{from: 1, to: 0}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: -2}, {X: 1, Y: -2}},
+		{from: 1, to: 2}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: -2}, {X: 1, Y: -2}},
+		{from: 2, to: 1}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: 2}, {X: -1, Y: 2}},
+		{from: 2, to: 3}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: -1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+		{from: 3, to: 2}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: -2}, {X: -1, Y: -2}},
+		{from: 3, to: 0}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: -2}, {X: -1, Y: -2}},
+		{from: 0, to: 3}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: -1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+	}
+
+	iKicks = map[rotationTransition][]Cell{
+		{from: 0, to: 1}: {{X: 0, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 1}, {X: 1, Y: -2}},
+		{from: 1, to: 0}: {{X: 0, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: -1}, {X: -1, Y: 2}},
+		{from: 1, to: 2}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: -2}, {X: 2, Y: 1}},
+		{from: 2, to: 1}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 2}, {X: -2, Y: -1}},
+		{from: 2, to: 3}: {{X: 0, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: -1}, {X: -1, Y: 2}},
+		{from: 3, to: 2}: {{X: 0, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 1}, {X: 1, Y: -2}},
+		{from: 3, to: 0}: {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: -2, Y: 0}, {X: 1, Y: 2}, {X: -2, Y: -1}},
+		{from: 0, to: 3}: {{X: 0, Y: 0}, {X: -1, Y: 0}, {X: 2, Y: 0}, {X: -1, Y: -2}, {X: 2, Y: 1}},
+	}
+
+	// The O piece never kicks, it only has to fit where it already is.
+	noKicks = []Cell{{X: 0, Y: 0}}
+)
@@ -1,7 +1,5 @@
 package tetris
 
-import "math/rand/v2"
-
 type Status int
 
 const (
@@ -10,18 +8,148 @@ const (
 	StatusGameOver
 )
 
+// GameOverReason tells how a game ended.
+type GameOverReason int
+
+const (
+	GameOverNone GameOverReason = iota
+	// GameOverBlockOut means a new piece spawned overlapping the stack.
+	GameOverBlockOut
+	// GameOverLockOut means a piece locked entirely above the visible field.
+	GameOverLockOut
+	// GameOverTopOut means garbage pushed the stack past the top of the buffer.
+	GameOverTopOut
+)
+
+func (r GameOverReason) String() string {
+	switch r {
+	case GameOverBlockOut:
+		return "Block Out"
+	case GameOverLockOut:
+		return "Lock Out"
+	case GameOverTopOut:
+		return "Top Out"
+	}
+	return ""
+}
+
+// Options configures a new game. Two games created with the same options and
+// fed the same inputs always end in the same state.
+type Options struct {
+	Width  int
+	Height int
+
+	Seed           uint64
+	RotationSystem RotationSystem // nil means SRS
+	Randomizer     RandomizerKind
+	Gravity        GravityCurve
+
+	PreviewCount int // Number of upcoming pieces shown, between 1 and MaxPreviewCount
+
+	LockDelay     *int // Frames a grounded piece waits before locking, 0 to lock on contact. nil means DefaultLockDelay
+	LockReset     LockResetMode
+	MaxLockResets int // Only used by LockResetMove
+
+	EntryDelay     int // ARE: frames between a lock and the next spawn
+	LineClearDelay int // Extra entry delay frames after a lock that clears lines, while the rows are still shown
+}
+
+const (
+	DefaultPreviewCount = 5
+	MaxPreviewCount     = 6
+
+	DefaultLockDelay     = 30 // 0.5 seconds at 60 FPS
+	DefaultMaxLockResets = 15
+)
+
+// Frames returns a pointer to n, for setting LockDelay.
+func Frames(n int) *int {
+	return &n
+}
+
+// LockResetMode decides which player actions restart the lock delay timer.
+type LockResetMode int
+
+const (
+	// LockResetMove restarts the timer on every successful move or rotation,
+	// up to MaxLockResets times per row the piece descends.
+	LockResetMove LockResetMode = iota
+	// LockResetInfinity restarts the timer on every successful move or rotation without limit.
+	LockResetInfinity
+	// LockResetStep only restarts the timer when the piece drops to a lower row.
+	LockResetStep
+)
+
+func (o Options) withDefaults() Options {
+	if o.Width <= 0 {
+		o.Width = 10
+	}
+	if o.Height <= 0 {
+		o.Height = 20
+	}
+	if o.RotationSystem == nil {
+		o.RotationSystem = NewSRS()
+	}
+	if o.PreviewCount <= 0 {
+		o.PreviewCount = DefaultPreviewCount
+	}
+	o.PreviewCount = min(o.PreviewCount, MaxPreviewCount)
+	if o.LockDelay == nil {
+		o.LockDelay = Frames(DefaultLockDelay)
+	}
+	o.LockDelay = Frames(max(*o.LockDelay, 0))
+	if o.MaxLockResets <= 0 {
+		o.MaxLockResets = DefaultMaxLockResets
+	}
+	return o
+}
+
+// LockResult reports what happened when a piece locked.
+type LockResult struct {
+	LockScore
+	Piece        *Piece // The piece as it locked
+	HardDropRows int    // Rows the piece fell when hard dropped, 0 for other locks
+	Rows         []int  // Cleared row indices from top to bottom, numbered before the stack collapsed
+	Level        int    // Level after the lock
+	LevelUp      bool
+}
+
 type GameState struct {
-	board        *Board
-	currentPiece *Piece
-	nextPiece    *Piece
+	options Options
+
+	board          *Board
+	rotationSystem RotationSystem
+	randomizer     Randomizer
+
+	currentPiece *Piece   // nil during the entry delay
+	queue        []*Piece // Upcoming pieces, the first one spawns next
+	heldPiece    *Piece
+	holdUsed     bool // Hold can only be used once per piece
 
+	scorer       *Scorer
 	score        int
 	linesCleared int
+	rotatedLast  bool // Whether the last successful action on the piece was a rotation
+	lastKick     int  // Index of the kick used by the last successful rotation
+
+	locks []LockResult // Pieces locked during the current Step
+
+	status         Status
+	gameOverReason GameOverReason
+
+	gravity         Gravity // Speed at the current level
+	gravityProgress Gravity // Part of a row gathered towards the next drop
+
+	lockTimer  int // Frames the current piece has spent on the ground
+	lockResets int // Lock delay resets used since the piece reached lowestY
+	lowestY    int // Lowest row the current piece has reached
 
-	status Status
+	entryTimer  int   // Frames left before the next piece spawns
+	pendingRows []int // Full rows removed when the entry delay ends
+}
 
-	frameCount   int
-	gravityDelay int // Frames between auto-drops
+func (gs *GameState) GetOptions() Options {
+	return gs.options
 }
 
 func (gs *GameState) GetLevel() int {
@@ -32,129 +160,303 @@ func (gs *GameState) GetLinesCleared() int {
 	return gs.linesCleared
 }
 
+// GetGravity returns how fast pieces fall at the current level.
+func (gs *GameState) GetGravity() Gravity {
+	return gs.gravity
+}
+
 func (gs *GameState) GetBoard() *Board {
 	return gs.board
 }
 
+// GetCurrentPiece returns the falling piece, or nil while the next one waits to spawn.
 func (gs *GameState) GetCurrentPiece() *Piece {
 	return gs.currentPiece
 }
 
 func (gs *GameState) GetNextPiece() *Piece {
-	return gs.nextPiece
+	return gs.queue[0]
+}
+
+// GetNextPieces returns up to n upcoming pieces, limited by the preview count.
+func (gs *GameState) GetNextPieces(n int) []*Piece {
+	return gs.queue[:max(0, min(n, len(gs.queue)))]
+}
+
+// GetHeldPiece returns the piece in the hold slot, or nil when it is empty.
+func (gs *GameState) GetHeldPiece() *Piece {
+	return gs.heldPiece
+}
+
+// CanHold reports whether Hold is available for the current piece.
+func (gs *GameState) CanHold() bool {
+	return !gs.holdUsed
 }
 
 func (gs *GameState) GetScore() int {
 	return gs.score
 }
 
+// Pause stops the game until Resume. A game that is over stays over.
 func (gs *GameState) Pause() {
-	gs.status = StatusPaused
+	if gs.status != StatusGameOver {
+		gs.status = StatusPaused
+	}
 }
 
 func (gs *GameState) Resume() {
-	gs.status = StatusPlaying
+	if gs.status != StatusGameOver {
+		gs.status = StatusPlaying
+	}
 }
 
 func (gs *GameState) IsGameOver() bool {
 	return gs.status == StatusGameOver
 }
 
-func NewGameState(width, height int) *GameState {
-	return &GameState{
-		board:        NewBoard(width, height),
-		currentPiece: spawnRandomPiece(width/2-2, -2),
-		nextPiece:    spawnRandomPiece(width/2-2, 0),
-		gravityDelay: 48, // ~0.8 seconds at 60 FPS
-		status:       StatusPlaying,
-	}
+// GetGameOverReason returns how the game ended, or GameOverNone while it is running.
+func (gs *GameState) GetGameOverReason() GameOverReason {
+	return gs.gameOverReason
 }
 
-var lastPieceID int
+func (gs *GameState) endGame(reason GameOverReason) {
+	gs.status = StatusGameOver
+	gs.gameOverReason = reason
+}
+
+// AddGarbage raises the stack by one row per entry in holes, each row empty
+// only in the given column. The game tops out when the stack is pushed past
+// the top of the buffer.
+func (gs *GameState) AddGarbage(holes []int) {
+	if gs.status == StatusGameOver {
+		return
+	}
+	if gs.board.AddGarbage(holes) {
+		gs.endGame(GameOverTopOut)
+		return
+	}
+	// Rows still waiting for the line clear delay moved up with the stack.
+	// The lock result shares the old slice, so it keeps the rows as they were
+	shifted := make([]int, len(gs.pendingRows))
+	for i, y := range gs.pendingRows {
+		shifted[i] = y - len(holes)
+	}
+	gs.pendingRows = shifted
 
-func pickRandomPiece() ShapeType {
-	for i := 0; i < 2; i++ {
-		id := rand.IntN(len(shapes))
-		if id != lastPieceID {
-			lastPieceID = id
-			return ShapeType(id)
+	// The falling piece is pushed up with the stack rather than overlapping it
+	for range holes {
+		if gs.currentPiece == nil || !gs.board.IsColliding(gs.currentPiece, 0, 0) {
+			return
 		}
+		gs.currentPiece.MoveUp()
+	}
+}
+
+// NewGameState creates a game on an empty board. Every source of randomness
+// is derived from opts.Seed.
+func NewGameState(opts Options) *GameState {
+	opts = opts.withDefaults()
+
+	gs := &GameState{
+		options:        opts,
+		board:          NewBoard(opts.Width, opts.Height),
+		rotationSystem: opts.RotationSystem,
+		randomizer:     NewRandomizer(opts.Randomizer, opts.Seed),
+		scorer:         NewScorer(),
+		gravity:        opts.Gravity.At(1),
+		status:         StatusPlaying,
 	}
-	return ShapeType(rand.IntN(len(shapes)))
+	gs.setCurrentPiece(gs.spawnRandomPiece(opts.Width/2-2, -2))
+	gs.queue = make([]*Piece, 0, opts.PreviewCount)
+	for range opts.PreviewCount {
+		gs.queue = append(gs.queue, gs.spawnRandomPiece(opts.Width/2-2, 0))
+	}
+	return gs
+}
+
+func (gs *GameState) spawnRandomPiece(spawnX, spawnY int) *Piece {
+	shape := gs.randomizer.Next()
+	return newSpawnedPiece(gs.rotationSystem, shape, spawnX, spawnY)
 }
 
-func spawnRandomPiece(spawnX, spawnY int) *Piece {
-	shape := pickRandomPiece()
-	return NewPiece(shape, spawnX, spawnY, 0)
+// popNextPiece takes the first piece from the queue and refills it from the randomizer.
+func (gs *GameState) popNextPiece() *Piece {
+	next := gs.queue[0]
+	copy(gs.queue, gs.queue[1:])
+	gs.queue[len(gs.queue)-1] = gs.spawnRandomPiece(gs.board.Width/2-2, 0)
+	return next
 }
 
+// Update advances the game by one tick without any player input. Every
+// duration in Options is counted in these ticks; see the sim package.
 func (gs *GameState) Update() {
 	if gs.status != StatusPlaying {
 		return
 	}
 
-	gs.frameCount++
-	if gs.frameCount >= gs.gravityDelay {
-		gs.frameCount = 0
-		gs.applyGravity()
+	if gs.currentPiece == nil {
+		gs.updateEntryDelay()
+		return
+	}
+
+	gs.gravityProgress += gs.gravity
+	rows := int(gs.gravityProgress / OneG)
+	gs.gravityProgress %= OneG
+	gs.applyGravity(rows)
+
+	gs.updateLockDelay()
+}
+
+// updateLockDelay counts the frames the current piece rests on the stack and
+// locks it once the lock delay runs out.
+func (gs *GameState) updateLockDelay() {
+	if !gs.board.IsColliding(gs.currentPiece, 0, 1) {
+		gs.lockTimer = 0
+		return
+	}
+
+	gs.lockTimer++
+	if gs.lockTimer >= *gs.options.LockDelay {
+		gs.lockCurrentPiece(0)
+	}
+}
+
+// updateEntryDelay counts down the frames between a lock and the next spawn.
+func (gs *GameState) updateEntryDelay() {
+	gs.entryTimer--
+	if gs.entryTimer <= 0 {
+		gs.spawnNextPiece()
+	}
+}
+
+// resetLockDelay restarts the lock timer after a successful move or rotation,
+// as far as the lock reset mode allows.
+func (gs *GameState) resetLockDelay() {
+	switch gs.options.LockReset {
+	case LockResetMove:
+		if gs.lockTimer > 0 && gs.lockResets < gs.options.MaxLockResets {
+			gs.lockTimer = 0
+			gs.lockResets++
+		}
+	case LockResetInfinity:
+		gs.lockTimer = 0
+	case LockResetStep:
+	}
+}
+
+// stepDown moves the current piece one row down, which always restarts the lock timer.
+func (gs *GameState) stepDown() {
+	gs.currentPiece.MoveDown()
+	gs.rotatedLast = false
+	gs.lockTimer = 0
+	if gs.currentPiece.Y > gs.lowestY {
+		gs.lowestY = gs.currentPiece.Y
+		gs.lockResets = 0
 	}
 }
 
 func (gs *GameState) MoveLeft() bool {
+	if gs.currentPiece == nil {
+		return false
+	}
 	if gs.board.IsColliding(gs.currentPiece, -1, 0) {
 		return false
 	}
 	gs.currentPiece.MoveLeft()
+	gs.rotatedLast = false
+	gs.resetLockDelay()
+	gs.applyInstantGravity()
 	return true
 }
 
 func (gs *GameState) MoveRight() bool {
+	if gs.currentPiece == nil {
+		return false
+	}
 	if gs.board.IsColliding(gs.currentPiece, 1, 0) {
 		return false
 	}
 	gs.currentPiece.MoveRight()
+	gs.rotatedLast = false
+	gs.resetLockDelay()
+	gs.applyInstantGravity()
 	return true
 }
 
 func (gs *GameState) MoveDown() bool {
+	if gs.currentPiece == nil {
+		return false
+	}
 	if gs.board.IsColliding(gs.currentPiece, 0, 1) {
 		return false
 	}
-	gs.currentPiece.MoveDown()
+	gs.stepDown()
+	gs.score += gs.scorer.SoftDrop(1)
 	return true
 }
 
+// Rotate turns the current piece clockwise, applying the rotation system's kicks when needed.
 func (gs *GameState) Rotate() bool {
-	oldRotation := gs.currentPiece.Rotation
-	oldPositionX := gs.currentPiece.X
-	oldPositionY := gs.currentPiece.Y
-	gs.currentPiece.Rotate()
-
-	if gs.board.IsColliding(gs.currentPiece, 0, 0) {
-
-		// Wall kicks: try shifting the piece left or right to see if it can fit after rotation
-		for _, offset := range []struct{ X, Y int }{
-			{X: 1, Y: 0},  // Right
-			{X: -1, Y: 0}, // Left
-		} {
-			if !gs.board.IsColliding(gs.currentPiece, offset.X, offset.Y) {
-				gs.currentPiece.X += offset.X
-				gs.currentPiece.Y += offset.Y
-				return true
-			}
+	return gs.rotate(1)
+}
+
+// RotateCCW turns the current piece counter-clockwise, applying the rotation system's kicks when needed.
+func (gs *GameState) RotateCCW() bool {
+	return gs.rotate(-1)
+}
+
+func (gs *GameState) rotate(direction int) bool {
+	if gs.currentPiece == nil {
+		return false
+	}
+	piece := gs.currentPiece
+	oldRotation := piece.Rotation
+	newRotation := piece.nextRotation(direction)
+	kicks := gs.rotationSystem.Kicks(gs.board, piece, newRotation)
+
+	piece.Rotation = newRotation
+	for i, offset := range kicks {
+		if !gs.board.IsColliding(piece, offset.X, offset.Y) {
+			piece.X += offset.X
+			piece.Y += offset.Y
+			gs.rotatedLast = true
+			gs.lastKick = i
+			gs.resetLockDelay()
+			gs.applyInstantGravity()
+			return true
 		}
+	}
+
+	// Rotation is not possible, revert to original state
+	piece.Rotation = oldRotation
+	return false
+}
 
-		// Rotation is not possible, revert to original state
-		gs.currentPiece.X = oldPositionX
-		gs.currentPiece.Y = oldPositionY
-		gs.currentPiece.Rotation = oldRotation
+// Hold puts the current piece in the hold slot and brings out the previously
+// held piece, or the next piece when the slot was empty. It can only be used
+// once until the current piece locks.
+func (gs *GameState) Hold() bool {
+	if gs.holdUsed || gs.currentPiece == nil {
 		return false
 	}
+	gs.holdUsed = true
+
+	held := gs.heldPiece
+	gs.heldPiece = newSpawnedPiece(gs.rotationSystem, gs.currentPiece.Shape, gs.board.Width/2-2, 0)
+
+	if held == nil {
+		held = gs.popNextPiece()
+	}
+	gs.setCurrentPiece(held)
 
 	return true
 }
 
+// GetShadowPiece returns where the current piece would land, or nil while the next one waits to spawn.
 func (gs *GameState) GetShadowPiece() *Piece {
+	if gs.currentPiece == nil {
+		return nil
+	}
 	shadowPiece := gs.currentPiece.Clone()
 	for !gs.board.IsColliding(shadowPiece, 0, 1) {
 		shadowPiece.MoveDown()
@@ -165,53 +467,117 @@ func (gs *GameState) GetShadowPiece() *Piece {
 }
 
 func (gs *GameState) HardDrop() {
+	if gs.currentPiece == nil {
+		return
+	}
+	cells := 0
 	for !gs.board.IsColliding(gs.currentPiece, 0, 1) {
 		gs.currentPiece.MoveDown()
+		cells++
+	}
+	if cells > 0 {
+		gs.rotatedLast = false
 	}
-	gs.lockCurrentPiece()
+	gs.score += gs.scorer.HardDrop(cells)
+	gs.lockCurrentPiece(cells)
 }
 
-func (gs *GameState) applyGravity() {
-	// Try to move piece down
-	if gs.board.IsColliding(gs.currentPiece, 0, 1) {
-		gs.lockCurrentPiece()
-	} else {
-		gs.currentPiece.MoveDown()
+// applyGravity drops the current piece by up to the given number of rows.
+// A grounded piece stays put until the lock delay runs out.
+func (gs *GameState) applyGravity(rows int) {
+	for range rows {
+		if gs.board.IsColliding(gs.currentPiece, 0, 1) {
+			return
+		}
+		gs.stepDown()
 	}
 }
 
-func (gs *GameState) lockCurrentPiece() {
-	gs.board.LockPiece(gs.currentPiece)
-
-	linesCleared := gs.board.ClearFullLines()
-	if linesCleared > 0 {
-		gs.addScore(linesCleared)
+// applyInstantGravity drops the current piece onto the stack straight away at
+// 20G, so it never hangs in the air after spawning or moving.
+func (gs *GameState) applyInstantGravity() {
+	if gs.gravity >= InstantGravity {
+		gs.applyGravity(gs.board.Height + 2) // From the spawn rows to the floor
 	}
+}
 
-	gs.currentPiece = gs.nextPiece
-	gs.currentPiece.Y = -2
-	gs.nextPiece = spawnRandomPiece(gs.board.Width/2-2, 0)
-
-	if gs.board.IsGameOver() {
-		gs.status = StatusGameOver
+// setCurrentPiece moves piece to the spawn rows just above the visible field
+// and makes it the falling piece. The game is blocked out when they are taken.
+func (gs *GameState) setCurrentPiece(piece *Piece) {
+	piece.Y = -2
+	gs.currentPiece = piece
+	if gs.board.IsColliding(piece, 0, 0) {
+		gs.endGame(GameOverBlockOut)
 		return
 	}
+
+	gs.lockTimer = 0
+	gs.lockResets = 0
+	gs.lowestY = piece.Y
+	gs.rotatedLast = false
+	gs.gravityProgress = 0
+	gs.applyInstantGravity()
 }
 
-func (gs *GameState) addScore(linesCleared int) {
-	points := map[int]int{
-		1: 100, // Single
-		2: 300, // Double
-		3: 500, // Triple
-		4: 800, // Tetris
+// LockResults returns the pieces locked during the last Step, in lock order.
+func (gs *GameState) LockResults() []LockResult {
+	return gs.locks
+}
+
+// lockCurrentPiece locks the falling piece after it hard dropped the given number of rows.
+func (gs *GameState) lockCurrentPiece(hardDropRows int) LockResult {
+	level := gs.GetLevel()
+	tspin := DetectTSpin(gs.board, gs.currentPiece, gs.rotatedLast, gs.lastKick)
+	lockedOut := gs.currentPiece.isAboveSkyline()
+	gs.board.LockPiece(gs.currentPiece)
+
+	rows := gs.board.FullRows()
+	result := LockResult{
+		LockScore:    gs.scorer.Lock(len(rows), tspin, len(rows) > 0 && gs.board.isEmptyAfterClearing(rows), level),
+		Piece:        gs.currentPiece,
+		HardDropRows: hardDropRows,
+		Rows:         rows,
 	}
+	gs.score += result.Points
+	gs.addLines(len(rows))
+	result.Level = gs.GetLevel()
+	result.LevelUp = result.Level > level
+	gs.locks = append(gs.locks, result)
+
+	gs.currentPiece = nil
+	if lockedOut {
+		gs.endGame(GameOverLockOut)
+		return result
+	}
+
+	gs.pendingRows = rows
+	gs.entryTimer = gs.options.EntryDelay
+	if len(rows) > 0 {
+		gs.entryTimer += gs.options.LineClearDelay
+	}
+	if gs.entryTimer <= 0 {
+		gs.spawnNextPiece()
+	}
+	return result
+}
+
+// spawnNextPiece collapses the cleared rows and brings out the next piece
+// once the entry delay is over.
+func (gs *GameState) spawnNextPiece() {
+	gs.board.ClearRows(gs.pendingRows)
+	gs.pendingRows = nil
+
+	gs.holdUsed = false
+	gs.setCurrentPiece(gs.popNextPiece())
+}
 
+// addLines counts cleared lines and moves gravity along the curve when the level goes up.
+func (gs *GameState) addLines(linesCleared int) {
 	currentLevel := gs.GetLevel()
-	gs.score += points[linesCleared] * (currentLevel)
 	gs.linesCleared += linesCleared
 
 	newLevel := gs.GetLevel()
 	if newLevel > currentLevel {
-		gs.gravityDelay = max(5, 48-newLevel*3)
+		gs.gravity = gs.options.Gravity.At(newLevel)
 	}
 }
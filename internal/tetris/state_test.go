@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLevel(t *testing.T) {
@@ -30,7 +31,7 @@ func TestLevel(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.linesCleared = tt.linesCleared
 			assert.Equal(t, tt.expectedLevel, gs.GetLevel())
 		})
@@ -41,39 +42,46 @@ func TestUpdate(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name         string
-		status       Status
-		frameCount   int
-		gravityDelay int
-		shouldApply  bool
+		name     string
+		status   Status
+		progress Gravity
+		gravity  Gravity
+		expectDy int
 	}{
 		{
-			name:         "paused game does nothing",
-			status:       StatusPaused,
-			frameCount:   50,
-			gravityDelay: 48,
-			shouldApply:  false,
+			name:     "paused game does nothing",
+			status:   StatusPaused,
+			progress: OneG - 1,
+			gravity:  GravityFromFrames(48),
+			expectDy: 0,
 		},
 		{
-			name:         "game over does nothing",
-			status:       StatusGameOver,
-			frameCount:   50,
-			gravityDelay: 48,
-			shouldApply:  false,
+			name:     "game over does nothing",
+			status:   StatusGameOver,
+			progress: OneG - 1,
+			gravity:  GravityFromFrames(48),
+			expectDy: 0,
 		},
 		{
-			name:         "playing game increments frame count",
-			status:       StatusPlaying,
-			frameCount:   0,
-			gravityDelay: 48,
-			shouldApply:  false,
+			name:     "playing game gathers gravity",
+			status:   StatusPlaying,
+			progress: 0,
+			gravity:  GravityFromFrames(48),
+			expectDy: 0,
 		},
 		{
-			name:         "should apply gravity after delay",
-			status:       StatusPlaying,
-			frameCount:   48,
-			gravityDelay: 48,
-			shouldApply:  true,
+			name:     "should apply gravity after delay",
+			status:   StatusPlaying,
+			progress: OneG - 1,
+			gravity:  GravityFromFrames(48),
+			expectDy: 1,
+		},
+		{
+			name:     "several rows per frame above 1G",
+			status:   StatusPlaying,
+			progress: 0,
+			gravity:  3*OneG + OneG/2,
+			expectDy: 3,
 		},
 	}
 
@@ -81,63 +89,61 @@ func TestUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.status = tt.status
-			gs.frameCount = tt.frameCount
-			gs.gravityDelay = tt.gravityDelay
+			gs.gravityProgress = tt.progress
+			gs.gravity = tt.gravity
 			originalY := gs.currentPiece.Y
 			gs.Update()
-			if tt.status != StatusPlaying {
-				assert.Equal(t, originalY, gs.currentPiece.Y)
-			}
+			assert.Equal(t, originalY+tt.expectDy, gs.currentPiece.Y)
 		})
 	}
 }
 
-func TestAddScore(t *testing.T) {
+func TestLockScore(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name                 string
-		linesCleared         int
-		currentLinesCleared  int
-		expectedScore        int
-		expectedGravityDelay int
+		name                string
+		linesCleared        int
+		currentLinesCleared int
+		expectedScore       int
+		expectedGravity     Gravity
 	}{
 		{
-			name:                 "single line",
-			linesCleared:         1,
-			currentLinesCleared:  0,
-			expectedScore:        100,
-			expectedGravityDelay: 48,
+			name:                "single line",
+			linesCleared:        1,
+			currentLinesCleared: 0,
+			expectedScore:       100,
+			expectedGravity:     GravityGuideline.At(1),
 		},
 		{
-			name:                 "double line",
-			linesCleared:         2,
-			currentLinesCleared:  0,
-			expectedScore:        300,
-			expectedGravityDelay: 48,
+			name:                "double line",
+			linesCleared:        2,
+			currentLinesCleared: 0,
+			expectedScore:       300,
+			expectedGravity:     GravityGuideline.At(1),
 		},
 		{
-			name:                 "triple line",
-			linesCleared:         3,
-			currentLinesCleared:  0,
-			expectedScore:        500,
-			expectedGravityDelay: 48,
+			name:                "triple line",
+			linesCleared:        3,
+			currentLinesCleared: 0,
+			expectedScore:       500,
+			expectedGravity:     GravityGuideline.At(1),
 		},
 		{
-			name:                 "tetris",
-			linesCleared:         4,
-			currentLinesCleared:  0,
-			expectedScore:        800,
-			expectedGravityDelay: 48,
+			name:                "tetris",
+			linesCleared:        4,
+			currentLinesCleared: 0,
+			expectedScore:       800,
+			expectedGravity:     GravityGuideline.At(1),
 		},
 		{
-			name:                 "level up decreases gravity delay",
-			linesCleared:         1,
-			currentLinesCleared:  9,
-			expectedScore:        100,
-			expectedGravityDelay: 42,
+			name:                "level up speeds up gravity",
+			linesCleared:        1,
+			currentLinesCleared: 9,
+			expectedScore:       100,
+			expectedGravity:     GravityGuideline.At(2),
 		},
 	}
 
@@ -145,11 +151,19 @@ func TestAddScore(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.linesCleared = tt.currentLinesCleared
-			gs.addScore(tt.linesCleared)
+			// Fill the bottom rows except column 0, where a vertical I piece completes them
+			for y := 20 - tt.linesCleared; y < 20; y++ {
+				for x := 1; x < 10; x++ {
+					gs.board.row(y)[x] = 1
+				}
+			}
+			gs.board.row(10)[9] = 1 // Keep the clear from being a perfect clear
+			gs.currentPiece = NewPiece(ShapeI, -2, 16, 1)
+			gs.lockCurrentPiece(0)
 			assert.Equal(t, tt.expectedScore, gs.score)
-			assert.Equal(t, tt.expectedGravityDelay, gs.gravityDelay)
+			assert.Equal(t, tt.expectedGravity, gs.gravity)
 		})
 	}
 }
@@ -178,7 +192,7 @@ func TestMoveLeft(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.currentPiece = NewPiece(ShapeO, tt.startX, 0, 0)
 			result := gs.MoveLeft()
 			assert.Equal(t, tt.expected, result)
@@ -215,7 +229,7 @@ func TestMoveRight(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.currentPiece = NewPiece(ShapeO, tt.startX, 0, 0)
 			result := gs.MoveRight()
 			assert.Equal(t, tt.expected, result)
@@ -252,7 +266,7 @@ func TestMoveDown(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.currentPiece = NewPiece(ShapeO, 4, tt.startY, 0)
 			result := gs.MoveDown()
 			assert.Equal(t, tt.expected, result)
@@ -300,7 +314,7 @@ func TestRotate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gs := NewGameState(10, 20)
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 			gs.currentPiece = NewPiece(tt.shape, tt.startX, tt.startY, tt.startRot)
 			result := gs.Rotate()
 			assert.Equal(t, tt.expectedSuccess, result)
@@ -309,10 +323,181 @@ func TestRotate(t *testing.T) {
 	}
 }
 
+func TestSRSWallKicks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		board           *Board
+		piece           *Piece
+		clockwise       bool
+		expectedSuccess bool
+		expectedX       int
+		expectedY       int
+		expectedRot     int
+	}{
+		{
+			name:            "free rotation does not kick",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeT, 4, 5, 0),
+			clockwise:       true,
+			expectedSuccess: true,
+			expectedX:       4,
+			expectedY:       5,
+			expectedRot:     1,
+		},
+		{
+			name:            "free counter-clockwise rotation does not kick",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeJ, 4, 5, 0),
+			clockwise:       false,
+			expectedSuccess: true,
+			expectedX:       4,
+			expectedY:       5,
+			expectedRot:     3,
+		},
+		{
+			name:            "T kicks right off the left wall",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeT, -1, 5, 1),
+			clockwise:       true,
+			expectedSuccess: true,
+			expectedX:       0,
+			expectedY:       5,
+			expectedRot:     2,
+		},
+		{
+			name:            "I kicks left off the right wall",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeI, 7, 5, 1),
+			clockwise:       true,
+			expectedSuccess: true,
+			expectedX:       6,
+			expectedY:       5,
+			expectedRot:     2,
+		},
+		{
+			name:            "I kicks one column right off the left wall",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeI, -1, 5, 3),
+			clockwise:       false,
+			expectedSuccess: true,
+			expectedX:       0,
+			expectedY:       5,
+			expectedRot:     2,
+		},
+		{
+			name:            "I kicks up off the floor",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeI, 3, 18, 0),
+			clockwise:       true,
+			expectedSuccess: true,
+			expectedX:       4,
+			expectedY:       16,
+			expectedRot:     1,
+		},
+		{
+			name: "T-spin triple uses the last kick",
+			board: boardFromRows(
+				"..#...",
+				"......",
+				"##.###",
+				"##..##",
+				"##.###",
+				"######",
+			),
+			piece:           NewPiece(ShapeT, 2, 0, 0),
+			clockwise:       true,
+			expectedSuccess: true,
+			expectedX:       1,
+			expectedY:       2,
+			expectedRot:     1,
+		},
+		{
+			name:            "O piece never kicks",
+			board:           NewBoard(10, 20),
+			piece:           NewPiece(ShapeO, 8, 5, 0),
+			clockwise:       true,
+			expectedSuccess: true,
+			expectedX:       8,
+			expectedY:       5,
+			expectedRot:     1,
+		},
+		{
+			name: "rotation fails when every kick is blocked",
+			board: boardFromRows(
+				"###.###",
+				"###.###",
+				"###.###",
+				"###.###",
+				"###.###",
+				"###.###",
+			),
+			piece:           NewPiece(ShapeI, 1, 0, 1),
+			clockwise:       true,
+			expectedSuccess: false,
+			expectedX:       1,
+			expectedY:       0,
+			expectedRot:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+			gs.board = tt.board
+			gs.currentPiece = tt.piece
+
+			var result bool
+			if tt.clockwise {
+				result = gs.Rotate()
+			} else {
+				result = gs.RotateCCW()
+			}
+
+			assert.Equal(t, tt.expectedSuccess, result)
+			assert.Equal(t, tt.expectedX, gs.currentPiece.X)
+			assert.Equal(t, tt.expectedY, gs.currentPiece.Y)
+			assert.Equal(t, tt.expectedRot, gs.currentPiece.Rotation)
+			assert.False(t, gs.board.IsColliding(gs.currentPiece, 0, 0))
+		})
+	}
+}
+
+func TestRotateCycles(t *testing.T) {
+	t.Parallel()
+
+	for _, shape := range []ShapeType{ShapeI, ShapeO, ShapeT, ShapeS, ShapeZ, ShapeJ, ShapeL} {
+		piece := NewPiece(shape, 0, 0, 0)
+		for i := 0; i < 4; i++ {
+			piece.Rotate()
+		}
+		assert.Equal(t, 0, piece.Rotation)
+
+		piece.RotateCCW()
+		assert.Equal(t, 3, piece.Rotation)
+	}
+}
+
+// boardFromRows builds a board from rows drawn top to bottom, where '#' marks an occupied cell.
+func boardFromRows(rows ...string) *Board {
+	b := NewBoard(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, c := range row {
+			if c == '#' {
+				b.row(y)[x] = 1
+			}
+		}
+	}
+	return b
+}
+
 func TestHardDrop(t *testing.T) {
 	t.Parallel()
 
-	gs := NewGameState(10, 20)
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
 	gs.currentPiece = NewPiece(ShapeO, 4, 0, 0)
 	piece := gs.currentPiece
 	gs.HardDrop()
@@ -325,11 +510,553 @@ func TestHardDrop(t *testing.T) {
 func TestLockCurrentPiece(t *testing.T) {
 	t.Parallel()
 
-	gs := NewGameState(10, 20)
-	originalNextPiece := gs.nextPiece
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	originalNextPiece := gs.GetNextPiece()
 	gs.currentPiece = NewPiece(ShapeO, 4, 18, 0)
-	gs.lockCurrentPiece()
+	gs.lockCurrentPiece(0)
 	assert.Equal(t, originalNextPiece, gs.currentPiece)
-	assert.NotEqual(t, originalNextPiece, gs.nextPiece)
-	assert.NotNil(t, gs.nextPiece)
+	assert.NotEqual(t, originalNextPiece, gs.GetNextPiece())
+	assert.NotNil(t, gs.GetNextPiece())
+}
+
+// playScript feeds one input per frame to the game: L/R move, U rotates
+// clockwise, Z counter-clockwise, D soft drops, H hard drops and '.' waits.
+func playScript(gs *GameState, script string) {
+	for _, input := range script {
+		switch input {
+		case 'L':
+			gs.MoveLeft()
+		case 'R':
+			gs.MoveRight()
+		case 'U':
+			gs.Rotate()
+		case 'Z':
+			gs.RotateCCW()
+		case 'D':
+			gs.MoveDown()
+		case 'H':
+			gs.HardDrop()
+		}
+		gs.Update()
+	}
+}
+
+// boardRows renders the board top to bottom, with '.' for empty cells and the color number otherwise.
+func boardRows(b *Board) []string {
+	rows := make([]string, b.Height)
+	for y := range rows {
+		row := make([]byte, b.Width)
+		for x := range row {
+			row[x] = '.'
+			if c := b.Cell(x, y); c != 0 {
+				row[x] = byte('0' + c)
+			}
+		}
+		rows[y] = string(row)
+	}
+	return rows
+}
+
+func TestDeterministicGame(t *testing.T) {
+	t.Parallel()
+
+	script := "LLLHLHRRHUURRRRHRHULLLHLHRRRRHURHUUHLLHUURRHUUURRRRRHULLLLLHULLLLHLLHRRHULHURRRRHLLLHRRRHUHUUURRRRHULLLLHRRHURRRRHRRRHUUULHULHH"
+	opts := Options{Width: 10, Height: 20, Seed: 2024}
+
+	gs := NewGameState(opts)
+	playScript(gs, script)
+
+	replayed := NewGameState(opts)
+	playScript(replayed, script)
+
+	assert.Equal(t, boardRows(gs.GetBoard()), boardRows(replayed.GetBoard()))
+	assert.Equal(t, gs.GetScore(), replayed.GetScore())
+	assert.Equal(t, gs.GetLinesCleared(), replayed.GetLinesCleared())
+
+	assert.Equal(t, []string{
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"...11.....",
+		"...61144..",
+		".1.66344..",
+		"11.623336.",
+		"167722.665",
+	}, boardRows(gs.GetBoard()))
+	assert.Equal(t, 2304, gs.GetScore())
+	assert.Equal(t, 9, gs.GetLinesCleared())
+	assert.False(t, gs.IsGameOver())
+}
+
+func TestGamesDoNotShareRandomness(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Width: 10, Height: 20, Seed: 99}
+	first := NewGameState(opts)
+	other := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	second := NewGameState(opts)
+
+	shapes := func(pieces []*Piece) []ShapeType {
+		var shapes []ShapeType
+		for _, piece := range pieces {
+			shapes = append(shapes, piece.Shape)
+		}
+		return shapes
+	}
+
+	// Dealing from another game in between must not change this game's
+	// sequence. Ten pieces stacked in the middle stay below the skyline.
+	for range 10 {
+		other.HardDrop()
+		require.False(t, first.IsGameOver())
+		assert.Equal(t, first.GetCurrentPiece().Shape, second.GetCurrentPiece().Shape)
+		assert.Equal(t, shapes(first.GetNextPieces(MaxPreviewCount)), shapes(second.GetNextPieces(MaxPreviewCount)))
+		first.HardDrop()
+		second.HardDrop()
+	}
+}
+
+func TestHold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, gs *GameState)
+	}{
+		{
+			name: "holding with an empty slot brings out the next piece",
+			run: func(t *testing.T, gs *GameState) {
+				t.Helper()
+				current := gs.currentPiece.Shape
+				next := gs.GetNextPiece()
+
+				assert.True(t, gs.Hold())
+				assert.Equal(t, current, gs.GetHeldPiece().Shape)
+				assert.Equal(t, next, gs.currentPiece)
+				assert.Equal(t, -2, gs.currentPiece.Y)
+				assert.NotEqual(t, next, gs.GetNextPiece())
+			},
+		},
+		{
+			name: "holding again swaps with the held piece",
+			run: func(t *testing.T, gs *GameState) {
+				t.Helper()
+				first := gs.currentPiece.Shape
+				gs.Hold()
+				gs.HardDrop()
+				second := gs.currentPiece.Shape
+				next := gs.GetNextPiece()
+
+				assert.True(t, gs.Hold())
+				assert.Equal(t, first, gs.currentPiece.Shape)
+				assert.Equal(t, second, gs.GetHeldPiece().Shape)
+				assert.Equal(t, next, gs.GetNextPiece())
+			},
+		},
+		{
+			name: "hold is allowed only once per piece drop",
+			run: func(t *testing.T, gs *GameState) {
+				t.Helper()
+				assert.True(t, gs.Hold())
+				held := gs.GetHeldPiece()
+				current := gs.currentPiece
+
+				assert.False(t, gs.CanHold())
+				assert.False(t, gs.Hold())
+				assert.Equal(t, held, gs.GetHeldPiece())
+				assert.Equal(t, current, gs.currentPiece)
+
+				gs.HardDrop()
+				assert.True(t, gs.CanHold())
+				assert.True(t, gs.Hold())
+			},
+		},
+		{
+			name: "held piece returns to spawn position and orientation",
+			run: func(t *testing.T, gs *GameState) {
+				t.Helper()
+				gs.currentPiece = NewPiece(ShapeT, 0, 10, 3)
+				gs.Hold()
+				gs.HardDrop()
+				gs.Hold()
+
+				assert.Equal(t, ShapeT, gs.currentPiece.Shape)
+				assert.Equal(t, 0, gs.currentPiece.Rotation)
+				assert.Equal(t, 3, gs.currentPiece.X)
+				assert.Equal(t, -2, gs.currentPiece.Y)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 5})
+			assert.Nil(t, gs.GetHeldPiece())
+			tt.run(t, gs)
+		})
+	}
+}
+
+func TestNextPieces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		previewCount int
+		requested    int
+		expectedLen  int
+	}{
+		{name: "default preview count", previewCount: 0, requested: 6, expectedLen: DefaultPreviewCount},
+		{name: "single preview", previewCount: 1, requested: 3, expectedLen: 1},
+		{name: "request fewer than queued", previewCount: 6, requested: 2, expectedLen: 2},
+		{name: "preview count is capped", previewCount: 10, requested: 10, expectedLen: MaxPreviewCount},
+		{name: "negative request", previewCount: 3, requested: -1, expectedLen: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 3, PreviewCount: tt.previewCount})
+			assert.Len(t, gs.GetNextPieces(tt.requested), tt.expectedLen)
+		})
+	}
+}
+
+func TestNextPiecesFollowRandomizer(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 8, PreviewCount: 6})
+	expected := deal(NewSevenBagRandomizer(8), 20)
+
+	assert.Equal(t, expected[0], gs.GetCurrentPiece().Shape)
+	for i := 1; i < 8; i++ {
+		queue := gs.GetNextPieces(6)
+		for j, piece := range queue {
+			assert.Equal(t, expected[i+j], piece.Shape)
+		}
+		gs.HardDrop()
+		assert.Equal(t, expected[i], gs.GetCurrentPiece().Shape)
+	}
+}
+
+func TestLockDelay(t *testing.T) {
+	t.Parallel()
+
+	// Each script entry is one frame: '.' waits, L/R move and U rotates before
+	// Update runs. The frame with the move counts as the first grounded frame.
+	tests := []struct {
+		name         string
+		mode         LockResetMode
+		script       string
+		expectLockAt int // Frame (1-based) on which the piece locks, 0 for never
+	}{
+		{
+			name:         "grounded piece locks when the delay runs out",
+			mode:         LockResetMove,
+			script:       "..........",
+			expectLockAt: 10,
+		},
+		{
+			name:         "move reset restarts the timer",
+			mode:         LockResetMove,
+			script:       "....L..........",
+			expectLockAt: 14,
+		},
+		{
+			name:         "move reset stops after the maximum reset count",
+			mode:         LockResetMove,
+			script:       "....L....R....L....R..........",
+			expectLockAt: 24,
+		},
+		{
+			name:         "infinity restarts the timer without limit",
+			mode:         LockResetInfinity,
+			script:       "....L....R....L....R....L....R..........",
+			expectLockAt: 39,
+		},
+		{
+			name:         "infinity still locks once the player stops",
+			mode:         LockResetInfinity,
+			script:       "....U....................",
+			expectLockAt: 14,
+		},
+		{
+			name:         "step reset ignores moves on the ground",
+			mode:         LockResetStep,
+			script:       "....L....R..........",
+			expectLockAt: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, LockDelay: Frames(10), LockReset: tt.mode, MaxLockResets: 3})
+			piece := NewPiece(ShapeT, 4, 0, 0)
+			gs.setCurrentPiece(piece)
+			for gs.MoveDown() {
+			}
+
+			lockedAt := 0
+			for frame, input := range tt.script {
+				switch input {
+				case 'L':
+					gs.MoveLeft()
+				case 'R':
+					gs.MoveRight()
+				case 'U':
+					gs.Rotate()
+				}
+				gs.Update()
+				if gs.currentPiece != piece {
+					lockedAt = frame + 1
+					break
+				}
+			}
+
+			assert.Equal(t, tt.expectLockAt, lockedAt)
+		})
+	}
+}
+
+func TestZeroLockDelay(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, LockDelay: Frames(0)})
+	assert.Equal(t, 0, *gs.GetOptions().LockDelay)
+
+	piece := NewPiece(ShapeT, 4, 0, 0)
+	gs.setCurrentPiece(piece)
+	for gs.MoveDown() {
+	}
+	gs.Update()
+	assert.NotEqual(t, piece, gs.currentPiece, "locks on the first frame it rests")
+	require.Len(t, gs.LockResults(), 1)
+
+	gs = NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	assert.Equal(t, DefaultLockDelay, *gs.GetOptions().LockDelay, "unset means the default")
+}
+
+func TestLockDelayStepReset(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, LockDelay: Frames(10), LockReset: LockResetStep})
+	gs.gravity = GravityFromFrames(3)
+	for x := range 4 {
+		gs.board.row(19)[x] = 1
+	}
+	// O piece rests on the ledge in columns 2-3
+	piece := NewPiece(ShapeO, 2, 0, 0)
+	gs.setCurrentPiece(piece)
+	for gs.MoveDown() {
+	}
+	assert.Equal(t, 17, piece.Y)
+
+	for range 5 {
+		gs.Update()
+	}
+	assert.Equal(t, 5, gs.lockTimer)
+
+	// Sliding off the ledge lets gravity pull the piece down, which restarts the timer
+	gs.MoveRight()
+	gs.MoveRight()
+	gs.MoveRight()
+	gs.Update()
+	assert.Equal(t, 18, piece.Y)
+	assert.Equal(t, 1, gs.lockTimer)
+
+	for range 8 {
+		gs.Update()
+	}
+	assert.Equal(t, piece, gs.currentPiece)
+	gs.Update()
+	assert.NotEqual(t, piece, gs.currentPiece)
+}
+
+func TestEntryDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		clearLine      bool
+		expectedFrames int
+	}{
+		{name: "lock without clears waits for the entry delay", clearLine: false, expectedFrames: 5},
+		{name: "line clears add the line clear delay", clearLine: true, expectedFrames: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, EntryDelay: 5, LineClearDelay: 20})
+			if tt.clearLine {
+				for x := 2; x < 10; x++ {
+					gs.board.row(19)[x] = 1
+				}
+			}
+			gs.board.row(10)[9] = 1
+			next := gs.GetNextPiece()
+			gs.currentPiece = NewPiece(ShapeO, 0, 5, 0)
+			gs.HardDrop()
+
+			frames := 0
+			for gs.GetCurrentPiece() == nil {
+				assert.Nil(t, gs.GetShadowPiece())
+				assert.False(t, gs.Apply(ActionMoveLeft))
+				if tt.clearLine {
+					// The cleared row stays on the board until the delay ends
+					assert.Equal(t, 1, gs.board.Cell(2, 19))
+				}
+				gs.Update()
+				frames++
+			}
+
+			assert.Equal(t, tt.expectedFrames, frames)
+			assert.Same(t, next, gs.GetCurrentPiece())
+			assert.True(t, gs.CanHold())
+			if tt.clearLine {
+				// The top half of the O piece collapsed into the bottom row
+				assert.Equal(t, []string{"44........"}, boardRows(gs.board)[19:])
+			}
+		})
+	}
+}
+
+func TestGarbageDuringLineClearDelay(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1, LineClearDelay: 20})
+	for x := 2; x < 10; x++ {
+		gs.board.row(19)[x] = 1
+	}
+	gs.currentPiece = NewPiece(ShapeO, 0, 5, 0)
+	gs.HardDrop()
+	require.Equal(t, []int{19}, gs.LockResults()[0].Rows)
+
+	gs.Update()
+	gs.AddGarbage([]int{5, 6})
+	for gs.GetCurrentPiece() == nil {
+		gs.Update()
+	}
+
+	assert.Equal(t, []string{
+		"44........",
+		"99999.9999",
+		"999999.999",
+	}, boardRows(gs.board)[17:], "the full row is cleared, not the garbage pushed under it")
+	assert.Equal(t, []int{19}, gs.LockResults()[0].Rows, "the lock still reports the row it filled")
+}
+
+func TestGameOverReasons(t *testing.T) {
+	t.Parallel()
+
+	// fillColumns fills every visible row in columns from and below to, so no row is full.
+	fillColumns := func(gs *GameState, from, to int) {
+		for y := range gs.board.Height {
+			for x := from; x < to; x++ {
+				gs.board.row(y)[x] = 1
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		run      func(gs *GameState)
+		expected GameOverReason
+	}{
+		{
+			name:     "still playing",
+			run:      func(gs *GameState) { gs.HardDrop() },
+			expected: GameOverNone,
+		},
+		{
+			name: "block out when the spawn rows are taken",
+			run: func(gs *GameState) {
+				for x := 1; x < gs.board.Width; x++ {
+					gs.board.row(-1)[x] = 1
+				}
+				gs.currentPiece = NewPiece(ShapeO, 6, 10, 0)
+				gs.HardDrop()
+			},
+			expected: GameOverBlockOut,
+		},
+		{
+			name: "lock out when a piece locks above the skyline",
+			run: func(gs *GameState) {
+				fillColumns(gs, 0, 9)
+				gs.currentPiece = NewPiece(ShapeO, 3, -6, 0)
+				gs.HardDrop()
+			},
+			expected: GameOverLockOut,
+		},
+		{
+			name: "a piece partly above the skyline is not a lock out",
+			run: func(gs *GameState) {
+				fillColumns(gs, 0, 4)
+				for y := range 2 {
+					gs.board.row(y)[4] = 0
+				}
+				gs.currentPiece = NewPiece(ShapeI, 4, -6, 1)
+				gs.HardDrop()
+			},
+			expected: GameOverNone,
+		},
+		{
+			name: "top out when garbage pushes the stack past the buffer",
+			run: func(gs *GameState) {
+				gs.board.row(-BufferRows)[0] = 1
+				gs.AddGarbage([]int{0})
+			},
+			expected: GameOverTopOut,
+		},
+		{
+			name:     "garbage below the buffer top",
+			run:      func(gs *GameState) { gs.AddGarbage([]int{0, 1, 2}) },
+			expected: GameOverNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+			tt.run(gs)
+			assert.Equal(t, tt.expected, gs.GetGameOverReason())
+			assert.Equal(t, tt.expected != GameOverNone, gs.IsGameOver())
+		})
+	}
+}
+
+func TestLockAboveSkylineKeepsCells(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGameState(Options{Width: 10, Height: 20, Seed: 1})
+	for y := 2; y < 20; y++ {
+		gs.board.row(y)[0] = 1
+	}
+	// A vertical I in column 0, away from the spawn, sticks out two rows above the field
+	gs.currentPiece = NewPiece(ShapeI, -2, -10, 1)
+	gs.HardDrop()
+
+	assert.False(t, gs.IsGameOver(), "the piece reaches into the field")
+	assert.NotZero(t, gs.board.Cell(0, -1))
+	assert.NotZero(t, gs.board.Cell(0, -2))
+	assert.Equal(t, 22, gs.board.StackHeight())
 }